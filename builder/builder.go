@@ -0,0 +1,196 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-01-21 14:30
+// version: 1.0.0
+// desc   : 
+
+package builder
+
+import (
+	"errors"
+	"github.com/yhyzgn/glue/dialect"
+	"github.com/yhyzgn/glue/internal"
+	"strings"
+)
+
+// ErrNoDialect 构建器未通过 Dialect 指定方言，dialect.Current 也未设置
+var ErrNoDialect = errors.New("glue: builder has no dialect, specify it by Dialect or set dialect.Current")
+
+// ErrUnsupported 方言不能生成该命令，如 Update 没有设置任何列
+var ErrUnsupported = errors.New("glue: command is not supported by the dialect")
+
+type InsertBuilder struct {
+	dialect internal.Dialect
+	table   string
+	columns []string
+	values  []interface{}
+}
+
+type UpdateBuilder struct {
+	dialect internal.Dialect
+	table   string
+	columns []string
+	values  []interface{}
+	where   []internal.Condition
+}
+
+type DeleteBuilder struct {
+	dialect internal.Dialect
+	table   string
+	where   []internal.Condition
+}
+
+func Insert(table string) *InsertBuilder {
+	return &InsertBuilder{dialect: dialect.Current, table: table}
+}
+
+func (b *InsertBuilder) Dialect(dialect internal.Dialect) *InsertBuilder {
+	b.dialect = dialect
+	return b
+}
+
+func (b *InsertBuilder) Set(column string, value interface{}) *InsertBuilder {
+	b.columns = append(b.columns, column)
+	b.values = append(b.values, value)
+	return b
+}
+
+func (b *InsertBuilder) Command() (*internal.Command, error) {
+	if b.dialect == nil {
+		return nil, ErrNoDialect
+	}
+	return supported(b.dialect.Insert(&internal.ExecValue{
+		Table:   b.table,
+		Columns: b.columns,
+		Values:  b.values,
+		Type:    internal.ExecInsert,
+	}))
+}
+
+func Update(table string) *UpdateBuilder {
+	return &UpdateBuilder{dialect: dialect.Current, table: table}
+}
+
+func (b *UpdateBuilder) Dialect(dialect internal.Dialect) *UpdateBuilder {
+	b.dialect = dialect
+	return b
+}
+
+func (b *UpdateBuilder) Set(column string, value interface{}) *UpdateBuilder {
+	b.columns = append(b.columns, column)
+	b.values = append(b.values, value)
+	return b
+}
+
+func (b *UpdateBuilder) Where(conditions ...internal.Condition) *UpdateBuilder {
+	b.where = append(b.where, conditions...)
+	return b
+}
+
+func (b *UpdateBuilder) Command() (*internal.Command, error) {
+	if b.dialect == nil {
+		return nil, ErrNoDialect
+	}
+	return supported(b.dialect.Update(&internal.ExecValue{
+		Table:   b.table,
+		Columns: b.columns,
+		Values:  b.values,
+		Where:   where(b.where),
+		Type:    internal.ExecUpdate,
+	}))
+}
+
+func Delete(table string) *DeleteBuilder {
+	return &DeleteBuilder{dialect: dialect.Current, table: table}
+}
+
+func (b *DeleteBuilder) Dialect(dialect internal.Dialect) *DeleteBuilder {
+	b.dialect = dialect
+	return b
+}
+
+func (b *DeleteBuilder) Where(conditions ...internal.Condition) *DeleteBuilder {
+	b.where = append(b.where, conditions...)
+	return b
+}
+
+func (b *DeleteBuilder) Command() (*internal.Command, error) {
+	if b.dialect == nil {
+		return nil, ErrNoDialect
+	}
+	return supported(b.dialect.Delete(&internal.ExecValue{
+		Table: b.table,
+		Where: where(b.where),
+		Type:  internal.ExecDelete,
+	}))
+}
+
+// supported 方言返回 nil 表示不支持该命令
+func supported(cmd *internal.Command) (*internal.Command, error) {
+	if cmd == nil {
+		return nil, ErrUnsupported
+	}
+	return cmd, nil
+}
+
+func where(conditions []internal.Condition) internal.Condition {
+	if len(conditions) == 0 {
+		return nil
+	}
+	return internal.And(conditions...)
+}
+
+// build 渲染条件，占位符接着 cmd 已有的参数编号
+func build(d internal.Dialect, cmd *internal.Command, condition internal.Condition) (string, []interface{}) {
+	if condition == nil {
+		return "", nil
+	}
+	return condition.Build(d, len(cmd.Args())+1)
+}
+
+// quote 引用标识符，`*` 和包含空格或括号的表达式原样输出，`t.col` 分别引用
+func quote(d internal.Dialect, column string) string {
+	if column == "*" || strings.ContainsAny(column, " ()") {
+		return column
+	}
+	parts := strings.Split(column, ".")
+	for i, part := range parts {
+		if part != "*" {
+			parts[i] = d.Quote(part)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+func quoteAll(d internal.Dialect, columns []string) string {
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = quote(d, column)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func quoteOrder(d internal.Dialect, order string) string {
+	order = strings.TrimSpace(order)
+	if idx := strings.LastIndex(order, " "); idx > 0 {
+		direction := strings.ToUpper(strings.TrimSpace(order[idx+1:]))
+		if direction == "ASC" || direction == "DESC" {
+			return quote(d, strings.TrimSpace(order[:idx])) + " " + direction
+		}
+	}
+	return quote(d, order)
+}
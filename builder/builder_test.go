@@ -0,0 +1,128 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-01-21 16:12
+// version: 1.0.0
+// desc   : 
+
+package builder
+
+import (
+	"fmt"
+	"github.com/yhyzgn/glue/dialect"
+	"github.com/yhyzgn/glue/internal"
+	"github.com/yhyzgn/glue/primary"
+	"reflect"
+	"testing"
+)
+
+type testDriver struct{}
+
+func (*testDriver) Name() string {
+	return "test"
+}
+
+func (*testDriver) Driver() string {
+	return "test"
+}
+
+func (*testDriver) Quote(key string) string {
+	return fmt.Sprintf(`"%s"`, key)
+}
+
+func (*testDriver) Placeholder(index int) string {
+	return fmt.Sprintf("$%d", index)
+}
+
+func (*testDriver) Database() string {
+	return ""
+}
+
+func TestSelectBuilder_Command(t *testing.T) {
+	cmd, err := Select("u.id", "u.name", "COUNT(o.id) AS total").
+		Dialect(dialect.New(new(testDriver))).
+		From("user u").
+		LeftJoin("order o", internal.Raw("o.user_id = u.id AND o.status = ?", 1)).
+		Where(internal.Eq("age", 18), internal.Or(internal.Like("name", "g%"), internal.IsNull("name"))).
+		GroupBy("u.id", "u.name").
+		Having(internal.Raw("COUNT(o.id) > ?", 2)).
+		OrderBy("total DESC", "u.id").
+		Limit(10).
+		Offset(20).
+		Command()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "SELECT\n" +
+		"\t\"u\".\"id\", \"u\".\"name\", COUNT(o.id) AS total\n" +
+		"FROM\n" +
+		"\tuser u\n" +
+		"\tLEFT JOIN order o ON o.user_id = u.id AND o.status = $1\n" +
+		"WHERE\n" +
+		"\t\"age\" = $2 AND (\"name\" LIKE $3 OR \"name\" IS NULL)\n" +
+		"GROUP BY\n" +
+		"\t\"u\".\"id\", \"u\".\"name\"\n" +
+		"HAVING\n" +
+		"\tCOUNT(o.id) > $4\n" +
+		"ORDER BY\n" +
+		"\t\"total\" DESC, \"u\".\"id\"\n" +
+		"LIMIT $5\n" +
+		"OFFSET $6"
+	if cmd.SQL() != expected {
+		t.Fatalf("unexpected sql:\n%s", cmd.SQL())
+	}
+	if !reflect.DeepEqual(cmd.Args(), []interface{}{1, 18, "g%", 2, 10, 20}) {
+		t.Fatalf("unexpected args: %v", cmd.Args())
+	}
+}
+
+func TestUpdateBuilder_Command(t *testing.T) {
+	cmd, err := Update("user").
+		Dialect(dialect.New(new(testDriver))).
+		Set("name", "glue").
+		Set("age", 18).
+		Where(internal.Eq("id", 1)).
+		Command()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cmd.SQL() != `UPDATE "user" SET "name" = $1, "age" = $2 WHERE "id" = $3` {
+		t.Fatalf("unexpected sql: %s", cmd.SQL())
+	}
+}
+
+func TestNoDialect(t *testing.T) {
+	if _, err := Select().From("user").Command(); err != ErrNoDialect {
+		t.Errorf("expect ErrNoDialect, got %v", err)
+	}
+	if _, err := Delete("user").Command(); err != ErrNoDialect {
+		t.Errorf("expect ErrNoDialect, got %v", err)
+	}
+}
+
+func TestUnsupported(t *testing.T) {
+	// 没有设置列的更新语句，方言返回 nil
+	if cmd, err := Update("user").Dialect(dialect.New(new(testDriver))).Where(internal.Eq("id", 1)).Command(); err != ErrUnsupported || cmd != nil {
+		t.Errorf("expect ErrUnsupported, got %v %v", cmd, err)
+	}
+	// 方言不支持序列，取下一个值的表达式无法生成
+	next := (&primary.Sequence{Name: "user_seq"}).Primary()
+	if cmd, err := Insert("user").Dialect(dialect.New(new(testDriver))).Set("id", next).Command(); err != ErrUnsupported || cmd != nil {
+		t.Errorf("expect ErrUnsupported, got %v %v", cmd, err)
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-01-21 10:05
+// version: 1.0.0
+// desc   : 
+
+package builder
+
+import (
+	"github.com/yhyzgn/glue/dialect"
+	"github.com/yhyzgn/glue/internal"
+	"strings"
+)
+
+type join struct {
+	kind  string
+	table string
+	on    internal.Condition
+}
+
+type SelectBuilder struct {
+	dialect internal.Dialect
+	columns []string
+	table   string
+	joins   []*join
+	where   []internal.Condition
+	groups  []string
+	having  []internal.Condition
+	orders  []string
+	limit   int
+	offset  int
+}
+
+// Select 使用 dialect.Current 构建查询，可通过 Dialect 指定其它方言，都未设置时 Command 返回 ErrNoDialect
+func Select(columns ...string) *SelectBuilder {
+	return &SelectBuilder{dialect: dialect.Current, columns: columns}
+}
+
+func (b *SelectBuilder) Dialect(dialect internal.Dialect) *SelectBuilder {
+	b.dialect = dialect
+	return b
+}
+
+func (b *SelectBuilder) From(table string) *SelectBuilder {
+	b.table = table
+	return b
+}
+
+func (b *SelectBuilder) Join(table string, on internal.Condition) *SelectBuilder {
+	return b.addJoin("INNER JOIN", table, on)
+}
+
+func (b *SelectBuilder) LeftJoin(table string, on internal.Condition) *SelectBuilder {
+	return b.addJoin("LEFT JOIN", table, on)
+}
+
+func (b *SelectBuilder) RightJoin(table string, on internal.Condition) *SelectBuilder {
+	return b.addJoin("RIGHT JOIN", table, on)
+}
+
+// Where 多次调用的条件之间为 AND 关系
+func (b *SelectBuilder) Where(conditions ...internal.Condition) *SelectBuilder {
+	b.where = append(b.where, conditions...)
+	return b
+}
+
+func (b *SelectBuilder) GroupBy(columns ...string) *SelectBuilder {
+	b.groups = append(b.groups, columns...)
+	return b
+}
+
+func (b *SelectBuilder) Having(conditions ...internal.Condition) *SelectBuilder {
+	b.having = append(b.having, conditions...)
+	return b
+}
+
+// OrderBy 列名后可跟 ASC 或 DESC，如 OrderBy("age DESC", "id")
+func (b *SelectBuilder) OrderBy(columns ...string) *SelectBuilder {
+	b.orders = append(b.orders, columns...)
+	return b
+}
+
+func (b *SelectBuilder) Limit(limit int) *SelectBuilder {
+	b.limit = limit
+	return b
+}
+
+func (b *SelectBuilder) Offset(offset int) *SelectBuilder {
+	b.offset = offset
+	return b
+}
+
+func (b *SelectBuilder) Command() (*internal.Command, error) {
+	d := b.dialect
+	if d == nil {
+		return nil, ErrNoDialect
+	}
+	columns := "*"
+	if len(b.columns) > 0 {
+		columns = quoteAll(d, b.columns)
+	}
+	cmd := internal.NewCommand("SELECT").
+		TabLine(columns).
+		Line("FROM").
+		TabLine(quote(d, b.table))
+	for _, jn := range b.joins {
+		cmd.TabLine(jn.kind).Space(quote(d, jn.table))
+		if sql, args := build(d, cmd, jn.on); sql != "" {
+			cmd.Space("ON").Space(sql).Arguments(args...)
+		}
+	}
+	if sql, args := build(d, cmd, internal.And(b.where...)); sql != "" {
+		cmd.Line("WHERE").TabLine(sql).Arguments(args...)
+	}
+	if len(b.groups) > 0 {
+		cmd.Line("GROUP BY").TabLine(quoteAll(d, b.groups))
+	}
+	if sql, args := build(d, cmd, internal.And(b.having...)); sql != "" {
+		cmd.Line("HAVING").TabLine(sql).Arguments(args...)
+	}
+	if len(b.orders) > 0 {
+		orders := make([]string, len(b.orders))
+		for i, order := range b.orders {
+			orders[i] = quoteOrder(d, order)
+		}
+		cmd.Line("ORDER BY").TabLine(strings.Join(orders, ", "))
+	}
+	if b.limit > 0 || b.offset > 0 {
+		cmd = d.Limit(cmd, b.limit, b.offset)
+	}
+	return cmd, nil
+}
+
+func (b *SelectBuilder) addJoin(kind, table string, on internal.Condition) *SelectBuilder {
+	b.joins = append(b.joins, &join{kind: kind, table: table, on: on})
+	return b
+}
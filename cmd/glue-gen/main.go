@@ -0,0 +1,95 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-02-03 14:30
+// version: 1.0.0
+// desc   : 
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"github.com/yhyzgn/glue/dialect"
+	_ "github.com/yhyzgn/glue/dialect/clickhouse"
+	_ "github.com/yhyzgn/glue/dialect/cockroach"
+	_ "github.com/yhyzgn/glue/dialect/mariadb"
+	_ "github.com/yhyzgn/glue/dialect/mssql"
+	_ "github.com/yhyzgn/glue/dialect/mysql"
+	_ "github.com/yhyzgn/glue/dialect/postgres"
+	_ "github.com/yhyzgn/glue/dialect/sqlite"
+	_ "github.com/yhyzgn/glue/dialect/tidb"
+	"github.com/yhyzgn/glue/gen"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// oracle 依赖 cgo 和 oracle 客户端，需使用 -tags oracle 编译
+
+func main() {
+	dialectName := flag.String("dialect", "", "database dialect, one of "+strings.Join(dialect.Dialects(), ", "))
+	dsn := flag.String("dsn", "", "data source name")
+	pkg := flag.String("package", "model", "package name of generated files")
+	out := flag.String("out", ".", "output directory")
+	tables := flag.String("tables", "", "comma separated tables to generate, all tables if empty")
+	flag.Parse()
+
+	if err := run(*dialectName, *dsn, *pkg, *out, *tables); err != nil {
+		fmt.Fprintln(os.Stderr, "glue-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(dialectName, dsn, pkg, out, tables string) error {
+	dl, ok := dialect.Lookup(dialectName)
+	if !ok {
+		return fmt.Errorf("unsupported dialect '%s'", dialectName)
+	}
+	if dsn == "" {
+		return fmt.Errorf("dsn is required")
+	}
+	db, err := sql.Open(dl.Driver(), dsn)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	config := &gen.Config{Package: pkg}
+	for _, table := range strings.Split(tables, ",") {
+		if table = strings.TrimSpace(table); table != "" {
+			config.Tables = append(config.Tables, table)
+		}
+	}
+	files, err := gen.Generate(context.Background(), dl, db, config)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(out, 0755); err != nil {
+		return err
+	}
+	for _, file := range files {
+		path := filepath.Join(out, file.Name)
+		if err := ioutil.WriteFile(path, file.Source, 0644); err != nil {
+			return err
+		}
+		fmt.Println(path)
+	}
+	return nil
+}
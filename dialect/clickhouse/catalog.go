@@ -0,0 +1,113 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-02-13 10:40
+// version: 1.0.0
+// desc   : 
+
+package clickhouse
+
+import (
+	"github.com/yhyzgn/glue/internal"
+)
+
+// 表结构从 system 库读取，clickhouse 没有自增列、二级索引和外键
+
+func (*ClickHouse) TablesQuery() *internal.Command {
+	return internal.NewCommand("SELECT name FROM system.tables").
+		Line("WHERE database = currentDatabase() AND NOT is_temporary").
+		Line("ORDER BY name")
+}
+
+func (*ClickHouse) ColumnsQuery(table string) *internal.Command {
+	return internal.NewCommand("SELECT name, type, startsWith(type, 'Nullable(') AS nullable,").
+		TabLine("nullIf(default_expression, '') AS dflt, comment AS remarks, position AS ordinal, 0 AS is_identity").
+		Line("FROM system.columns").
+		Line("WHERE database = currentDatabase() AND table = ?").
+		Line("ORDER BY position").
+		Arguments(table)
+}
+
+func (*ClickHouse) PrimaryKeysQuery(table string) *internal.Command {
+	return internal.NewCommand("SELECT name FROM system.columns").
+		Line("WHERE database = currentDatabase() AND table = ? AND is_in_primary_key = 1").
+		Line("ORDER BY position").
+		Arguments(table)
+}
+
+func (*ClickHouse) IndexesQuery(table string) *internal.Command {
+	return internal.NewCommand("SELECT '' AS name, '' AS column_name, 0 AS is_unique FROM system.one WHERE 0")
+}
+
+func (*ClickHouse) ForeignKeysQuery(table string) *internal.Command {
+	return internal.NewCommand("SELECT '' AS name, '' AS column_name, '' AS ref_table, '' AS ref_column FROM system.one WHERE 0")
+}
+
+// 存在性检查同样读取 system 库，schema 为数据库名
+
+func (*ClickHouse) HasTable(schema, name string) *internal.Command {
+	return internal.NewCommand("SELECT").
+		TabLine("COUNT(*)").
+		Line("FROM").
+		TabLine("system.tables").
+		Line("WHERE").
+		TabLine("database = ?").
+		TabLine("AND name = ?").
+		Arguments(schema, name)
+}
+
+func (*ClickHouse) Columns(schema, table string) *internal.Command {
+	return internal.NewCommand("SELECT").
+		TabLine("*").
+		Line("FROM").
+		TabLine("system.columns").
+		Line("WHERE").
+		TabLine("database = ?").
+		TabLine("AND table = ?").
+		Line("ORDER BY").
+		TabLine("position ASC").
+		Arguments(schema, table)
+}
+
+func (*ClickHouse) HasColumn(schema, table, column string) *internal.Command {
+	return internal.NewCommand("SELECT").
+		TabLine("COUNT(*)").
+		Line("FROM").
+		TabLine("system.columns").
+		Line("WHERE").
+		TabLine("database = ?").
+		TabLine("AND table = ?").
+		TabLine("AND name = ?").
+		Arguments(schema, table, column)
+}
+
+// HasIndex 只有数据跳数索引
+func (*ClickHouse) HasIndex(schema, table, name string) *internal.Command {
+	return internal.NewCommand("SELECT").
+		TabLine("COUNT(*)").
+		Line("FROM").
+		TabLine("system.data_skipping_indices").
+		Line("WHERE").
+		TabLine("database = ?").
+		TabLine("AND table = ?").
+		TabLine("AND name = ?").
+		Arguments(schema, table, name)
+}
+
+// HasForeignKey clickhouse 没有外键，始终不存在
+func (*ClickHouse) HasForeignKey(schema, table, name string) *internal.Command {
+	return internal.NewCommand("SELECT 0")
+}
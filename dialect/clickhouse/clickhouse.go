@@ -0,0 +1,181 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-02-13 10:05
+// version: 1.0.0
+// desc   : 
+
+package clickhouse
+
+import (
+	"fmt"
+	"github.com/yhyzgn/glue/dialect"
+	"github.com/yhyzgn/glue/internal"
+	"reflect"
+	"strings"
+)
+
+// DefaultEngine 建表时默认使用的表引擎
+const DefaultEngine = "MergeTree()"
+
+// ClickHouse 面向分析的列式数据库，没有自增、外键和事务，更新和删除通过异步的 ALTER TABLE mutation 完成
+type ClickHouse struct {
+	*dialect.Creator
+	engine string
+}
+
+func Dialect() *ClickHouse {
+	dl := &ClickHouse{Creator: dialect.New(new(clickhouse)), engine: DefaultEngine}
+	dl.Bind(dl)
+	return dl
+}
+
+// Engine 设置建表时使用的表引擎，如 ReplacingMergeTree()
+func (c *ClickHouse) Engine(engine string) *ClickHouse {
+	c.engine = engine
+	return c
+}
+
+func (*ClickHouse) SQLType(field *reflect.StructField) string {
+	return types.SQLType(field)
+}
+
+// Transactional clickhouse 不支持事务，迁移时逐条执行，也没有唯一约束，migrate.Runner 的迁移锁不能互斥
+func (*ClickHouse) Transactional() bool {
+	return false
+}
+
+// CreateTable 可空列包装为 Nullable(T)，主键作为排序键，忽略外键和二级索引
+func (c *ClickHouse) CreateTable(definition *internal.Definition) []*internal.Command {
+	if definition == nil || len(definition.Fields) == 0 {
+		return nil
+	}
+	cmd := internal.NewCommand("CREATE TABLE").Space(c.Quote(definition.TableName)).Space("(")
+	for idx, field := range definition.Fields {
+		if idx > 0 {
+			cmd.Append(",")
+		}
+		cmd.TabLine(c.column(field.Column, field.SQLType, field.Comment, field.NotNull, field.Default))
+	}
+	cmd.Line(")")
+
+	keys := make([]string, len(definition.PrimaryKeys))
+	for i, field := range definition.PrimaryKeys {
+		keys[i] = c.Quote(field.Column)
+	}
+	cmd.Line("ENGINE = " + c.engine)
+	if len(keys) == 0 {
+		cmd.Line("ORDER BY tuple()")
+	} else {
+		cmd.Line(fmt.Sprintf("ORDER BY (%s)", strings.Join(keys, ", ")))
+	}
+	return []*internal.Command{cmd}
+}
+
+// ModifyColumn clickhouse 不能在修改类型时重命名列，rename 与 column 不同时先改名再修改
+func (c *ClickHouse) ModifyColumn(table, column, rename, tpy, comment string, notNull bool, defValue interface{}) []*internal.Command {
+	cmd := internal.NewCommand(fmt.Sprintf("ALTER TABLE %s", c.Quote(table)))
+	if rename != "" && rename != column {
+		cmd.Space(fmt.Sprintf("RENAME COLUMN %s TO %s,", c.Quote(column), c.Quote(rename)))
+		column = rename
+	}
+	return []*internal.Command{cmd.Space("MODIFY COLUMN").Space(c.column(column, tpy, comment, notNull, defValue))}
+}
+
+func (c *ClickHouse) AddColumn(table, column, tpy, comment string, notNull bool, defValue interface{}) []*internal.Command {
+	return []*internal.Command{internal.NewCommand(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", c.Quote(table), c.column(column, tpy, comment, notNull, defValue)))}
+}
+
+// NormalizeType 可空的列类型为 Nullable(T)，可空性单独比较，这里只保留 T
+func (*ClickHouse) NormalizeType(tpy string) string {
+	tpy = strings.TrimSpace(tpy)
+	if strings.HasPrefix(tpy, "Nullable(") && strings.HasSuffix(tpy, ")") {
+		tpy = tpy[len("Nullable(") : len(tpy)-1]
+	}
+	return tpy
+}
+
+// AddIndex clickhouse 只有数据跳数索引，不对应普通索引，忽略
+func (*ClickHouse) AddIndex(table, name string, indexes []*internal.Index) *internal.Command {
+	return nil
+}
+
+// AddForeignKey clickhouse 不支持外键，忽略
+func (*ClickHouse) AddForeignKey(table string, key *internal.ForeignKey) *internal.Command {
+	return nil
+}
+
+// Delete 生成 ALTER TABLE ... DELETE，条件与通用实现一致
+func (c *ClickHouse) Delete(value *internal.ExecValue) *internal.Command {
+	if !value.Valid() {
+		return nil
+	}
+	conditions := make([]internal.Condition, 0, len(value.Columns)+1)
+	for i, column := range value.Columns {
+		conditions = append(conditions, internal.Eq(column, value.Values[i]))
+	}
+	conditions = append(conditions, value.Where)
+	return c.where(internal.NewCommand(fmt.Sprintf("ALTER TABLE %s DELETE", c.Quote(value.Table))), internal.And(conditions...))
+}
+
+func (c *ClickHouse) Remove(value *internal.ExecValue) *internal.Command {
+	if !value.Valid() || len(value.Columns) == 0 {
+		return nil
+	}
+	return c.update(value)
+}
+
+func (c *ClickHouse) Update(value *internal.ExecValue) *internal.Command {
+	if !value.Valid() || len(value.Columns) == 0 {
+		return nil
+	}
+	return c.update(value)
+}
+
+// update 生成 ALTER TABLE ... UPDATE，不允许修改排序键中的列
+func (c *ClickHouse) update(value *internal.ExecValue) *internal.Command {
+	assignments := make([]string, len(value.Columns))
+	for i, column := range value.Columns {
+		assignments[i] = fmt.Sprintf("%s = %s", c.Quote(column), c.Placeholder(i+1))
+	}
+	cmd := internal.NewCommand(fmt.Sprintf("ALTER TABLE %s UPDATE %s", c.Quote(value.Table), strings.Join(assignments, ", "))).Arguments(value.Values...)
+	return c.where(cmd, value.Where)
+}
+
+// where mutation 必须带 WHERE 子句，没有条件时使用 WHERE 1
+func (c *ClickHouse) where(cmd *internal.Command, condition internal.Condition) *internal.Command {
+	if condition != nil {
+		if sql, args := condition.Build(c, len(cmd.Args())+1); sql != "" {
+			return cmd.Space("WHERE").Space(sql).Arguments(args...)
+		}
+	}
+	return cmd.Space("WHERE 1")
+}
+
+func (c *ClickHouse) column(column, tpy, comment string, notNull bool, defValue interface{}) string {
+	if !notNull && !strings.HasPrefix(tpy, "Nullable(") {
+		tpy = fmt.Sprintf("Nullable(%s)", tpy)
+	}
+	result := c.Quote(column) + " " + tpy
+	// 默认值与 Creator.CreateTable 一致按原样写入，可以是 now() 等表达式
+	if defValue != nil {
+		result += fmt.Sprintf(" DEFAULT %v", defValue)
+	}
+	if comment != "" {
+		result += " COMMENT " + c.Literal(comment)
+	}
+	return result
+}
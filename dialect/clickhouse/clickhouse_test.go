@@ -0,0 +1,75 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-02-28 11:00
+// version: 1.0.0
+// desc   : 
+
+package clickhouse
+
+import (
+	"github.com/yhyzgn/glue/internal"
+	"testing"
+)
+
+type visit struct {
+	internal.TableModel
+	Page  string `glue:"size:255;notnull"`
+	Agent string
+	Hits  int64 `glue:"notnull;default:0"`
+}
+
+func (*visit) TableName() string {
+	return "visit"
+}
+
+func TestCreateTable(t *testing.T) {
+	dl := Dialect().Engine("ReplacingMergeTree()")
+	def, err := internal.Parse(dl, &visit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	commands := dl.CreateTable(def)
+	expected := "CREATE TABLE `visit` (\n\t`page` String,\n\t`agent` Nullable(String),\n\t`hits` Int64 DEFAULT 0\n)\nENGINE = ReplacingMergeTree()\nORDER BY tuple()"
+	if len(commands) != 1 || commands[0].SQL() != expected {
+		t.Errorf("unexpected create table:\n%s", commands[0].SQL())
+	}
+	// 默认值按原样写入，表达式不会被当作字符串
+	if commands := dl.AddColumn("visit", "at", "DateTime", "", true, "now()"); commands[0].SQL() != "ALTER TABLE `visit` ADD COLUMN `at` DateTime DEFAULT now()" {
+		t.Errorf("unexpected add column: %s", commands[0].SQL())
+	}
+	if cmd := dl.AddIndex("visit", "idx_visit_page", []*internal.Index{{Name: "idx_visit_page", Column: "page"}}); cmd != nil {
+		t.Errorf("unexpected index: %s", cmd.SQL())
+	}
+}
+
+func TestMutation(t *testing.T) {
+	dl := Dialect()
+	value := &internal.ExecValue{Table: "visit", Columns: []string{"hits"}, Values: []interface{}{1}, Where: internal.Eq("page", "/")}
+	if cmd := dl.Update(value); cmd.SQL() != "ALTER TABLE `visit` UPDATE `hits` = ? WHERE `page` = ?" || len(cmd.Args()) != 2 {
+		t.Errorf("unexpected update: %s %v", cmd.SQL(), cmd.Args())
+	}
+	if cmd := dl.Delete(&internal.ExecValue{Table: "visit", Columns: []string{"page"}, Values: []interface{}{"/"}}); cmd.SQL() != "ALTER TABLE `visit` DELETE WHERE `page` = ?" {
+		t.Errorf("unexpected delete: %s", cmd.SQL())
+	}
+	mismatched := &internal.ExecValue{Table: "visit", Columns: []string{"page", "hits"}, Values: []interface{}{"/"}}
+	if dl.Update(mismatched) != nil || dl.Delete(mismatched) != nil {
+		t.Error("mismatched value should not generate commands")
+	}
+	if dl.Transactional() {
+		t.Error("clickhouse should not be transactional")
+	}
+}
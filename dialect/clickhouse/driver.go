@@ -0,0 +1,65 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-02-13 09:15
+// version: 1.0.0
+// desc   : 
+
+package clickhouse
+
+import (
+	"database/sql"
+	_ "github.com/ClickHouse/clickhouse-go"
+	"github.com/yhyzgn/glue/dialect"
+	"github.com/yhyzgn/glue/internal"
+)
+
+// 驱动类型未导出，通过 sql.Open 取得驱动实例，sql.Open 不会建立连接
+func init() {
+	db, err := sql.Open("clickhouse", "")
+	if err != nil {
+		panic(err)
+	}
+	dialect.Register("clickhouse", db.Driver(), func() internal.Dialect {
+		return Dialect()
+	})
+}
+
+// clickhouse 的反引号标识符中用反斜杠转义反引号
+var quoter = dialect.Quoter{Open: "`", Close: "`", Escape: "\\`"}
+
+type clickhouse struct {
+}
+
+func (*clickhouse) Name() string {
+	return "clickhouse"
+}
+
+func (*clickhouse) Driver() string {
+	return "clickhouse"
+}
+
+func (*clickhouse) Quote(key string) string {
+	return quoter.Quote(key)
+}
+
+func (*clickhouse) Placeholder(index int) string {
+	return "?"
+}
+
+func (*clickhouse) Database() string {
+	return "SELECT currentDatabase()"
+}
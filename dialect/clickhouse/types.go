@@ -0,0 +1,51 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-02-13 09:40
+// version: 1.0.0
+// desc   : 
+
+package clickhouse
+
+import (
+	"fmt"
+	"github.com/yhyzgn/glue/dialect"
+)
+
+// clickhouse 的字符串不限长度，可空性由 Nullable(T) 表示，在建表时包装
+var types = &dialect.Types{
+	Bool:    "UInt8",
+	Int8:    "Int8",
+	Int16:   "Int16",
+	Int32:   "Int32",
+	Int64:   "Int64",
+	Uint8:   "UInt8",
+	Uint16:  "UInt16",
+	Uint32:  "UInt32",
+	Uint64:  "UInt64",
+	Float32: "Float32",
+	Float64: "Float64",
+	Time:    "DateTime",
+	String: func(size int) string {
+		return "String"
+	},
+	Bytes: func(size int) string {
+		return "String"
+	},
+	Decimal: func(precision, scale int) string {
+		return fmt.Sprintf("Decimal(%d, %d)", precision, scale)
+	},
+}
@@ -0,0 +1,57 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-02-12 10:10
+// version: 1.0.0
+// desc   : 
+
+package cockroach
+
+import (
+	"fmt"
+	"github.com/yhyzgn/glue/dialect"
+	"github.com/yhyzgn/glue/dialect/postgres"
+	"github.com/yhyzgn/glue/internal"
+	"reflect"
+)
+
+// Cockroach 兼容 postgres 语法，分布式环境下顺序自增会产生写入热点，自增主键改用 unique_rowid() 生成
+type Cockroach struct {
+	*postgres.Postgres
+}
+
+func Dialect() *Cockroach {
+	dl := &Cockroach{&postgres.Postgres{Creator: dialect.New(new(cockroach))}}
+	dl.Bind(dl)
+	return dl
+}
+
+// Identity unique_rowid() 生成的是 INT8，自增主键字段由 Validate 限制为 64 位整数
+func (*Cockroach) Identity(table *internal.Definition, field *internal.Field) (string, bool) {
+	return "INT8 NOT NULL DEFAULT unique_rowid()", false
+}
+
+// Validate unique_rowid() 生成的值超出 32 位整数的范围，取回主键时会溢出，自增主键字段必须是 int64 或 uint64
+func (c *Cockroach) Validate(definition *internal.Definition) error {
+	field := dialect.IdentityOf(c, definition)
+	if field == nil {
+		return nil
+	}
+	if kind := field.ElmType.Kind(); kind != reflect.Int64 && kind != reflect.Uint64 {
+		return fmt.Errorf("glue: identity field %s.%s must be int64 for cockroach unique_rowid(), got %v", definition.Model.ElmType.Name(), field.Name, field.Type)
+	}
+	return nil
+}
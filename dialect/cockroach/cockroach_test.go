@@ -0,0 +1,73 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-02-28 10:30
+// version: 1.0.0
+// desc   : 
+
+package cockroach
+
+import (
+	"github.com/yhyzgn/glue/internal"
+	"github.com/yhyzgn/glue/primary"
+	"strings"
+	"testing"
+)
+
+type event struct {
+	internal.TableModel
+	ID   int64  `glue:"pk"`
+	Name string `glue:"size:64"`
+}
+
+func (*event) TableName() string {
+	return "event"
+}
+
+func (*event) PrimaryStrategy() internal.Strategy {
+	return &primary.AutoIncrement{}
+}
+
+type smallEvent struct {
+	internal.TableModel
+	ID int32 `glue:"pk"`
+}
+
+func (*smallEvent) PrimaryStrategy() internal.Strategy {
+	return &primary.AutoIncrement{}
+}
+
+func TestCreateTable(t *testing.T) {
+	dl := Dialect()
+	def, err := internal.Parse(dl, &event{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sql := dl.CreateTable(def)[0].SQL(); !strings.Contains(sql, `"id" INT8 NOT NULL DEFAULT unique_rowid(),`) || !strings.Contains(sql, `PRIMARY KEY ("id")`) {
+		t.Errorf("unexpected create table:\n%s", sql)
+	}
+	// unique_rowid() 的值超出 int32 的范围
+	if _, err := internal.Parse(dl, &smallEvent{}); err == nil || !strings.Contains(err.Error(), "must be int64") {
+		t.Errorf("int32 identity should be rejected: %v", err)
+	}
+}
+
+func TestInsert(t *testing.T) {
+	cmd := Dialect().Insert(&internal.ExecValue{Table: "event", Columns: []string{"name"}, Values: []interface{}{"glue"}, Primary: "id"})
+	if cmd.SQL() != `INSERT INTO "event" ("name") VALUES ($1) RETURNING "id"` {
+		t.Errorf("unexpected insert: %s", cmd.SQL())
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-02-12 09:30
+// version: 1.0.0
+// desc   : 
+
+package cockroach
+
+import (
+	"fmt"
+	_ "github.com/lib/pq"
+	"github.com/yhyzgn/glue/dialect"
+	"github.com/yhyzgn/glue/internal"
+)
+
+// cockroach 使用 postgres 协议和驱动，从 *sql.DB 查找时得到的是 postgres 方言
+func init() {
+	dialect.Register("cockroach", nil, func() internal.Dialect {
+		return Dialect()
+	})
+}
+
+// cockroach 与 postgres 一致，将标识符折叠为小写
+var quoter = dialect.Quoter{Open: `"`, Close: `"`, Folding: dialect.FoldLower}
+
+type cockroach struct {
+}
+
+func (*cockroach) Name() string {
+	return "cockroach"
+}
+
+func (*cockroach) Driver() string {
+	return "postgres"
+}
+
+func (*cockroach) Quote(key string) string {
+	return quoter.Quote(key)
+}
+
+func (*cockroach) Placeholder(index int) string {
+	return fmt.Sprintf("$%d", index)
+}
+
+func (*cockroach) Database() string {
+	return "SELECT current_schema()"
+}
+
+func (*cockroach) Fold(name string) string {
+	return quoter.Folding.Fold(name)
+}
@@ -21,22 +21,82 @@
 package dialect
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/yhyzgn/glue/internal"
-	"github.com/yhyzgn/glue/primary"
 	"reflect"
+	"sort"
 	"strings"
+	"sync"
 )
 
+// RowComparer 支持行值比较 (a, b) > (?, ?) 的方言实现此接口，游标分页时生成更紧凑的条件
+type RowComparer interface {
+	RowComparison() bool
+}
+
+// Returner 插入时能够直接取回生成主键的方言实现此接口，
+// output 位于 VALUES 之前（如 mssql 的 OUTPUT），suffix 追加在语句末尾（如 RETURNING），index 为下一个占位符序号
+type Returner interface {
+	Returning(column string, index int) (output, suffix string)
+}
+
+// Identifier 支持自增主键的方言实现此接口，definition 为列名之后的完整列定义，
+// inline 为 true 时列定义中已包含主键约束（如 sqlite），不再单独声明 PRIMARY KEY
+type Identifier interface {
+	Identity(table *internal.Definition, field *internal.Field) (definition string, inline bool)
+}
+
+// Commenter 方言的列注释方式，inline 追加在列定义之后，cmd 为建表后单独执行的语句，都为空时忽略注释，
+// 未实现时使用 COMMENT ON COLUMN 语句
+type Commenter interface {
+	Comment(table string, field *internal.Field) (inline string, cmd *internal.Command)
+}
+
+// TypeNormalizer 数据库返回的列类型与建表时的写法不一致的方言实现此接口，
+// AutoMigrate 比较列类型前对两边分别调用 NormalizeType，如 mysql 的 int(11) 与 INT
+type TypeNormalizer interface {
+	NormalizeType(tpy string) string
+}
+
+// Sequencer 支持序列的方言实现此接口，NextValue 返回取下一个值的表达式
+type Sequencer interface {
+	CreateSequence(name string, start, increment int64) *internal.Command
+	DropSequence(name string) *internal.Command
+	NextValue(name string) string
+}
+
+// Transactional 不支持事务的方言实现此接口并返回 false，如 clickhouse
+type Transactional interface {
+	Transactional() bool
+}
+
 type Creator struct {
-	driver internal.Driver
+	driver  internal.Driver
+	dialect internal.Dialect
+	mu      sync.Mutex
+	schema  string
 }
 
 func New(driver internal.Driver) *Creator {
 	return &Creator{driver: driver}
 }
 
+// Bind 绑定嵌入了 Creator 的方言，使 Creator 内部的调用能走到方言覆盖后的方法
+func (c *Creator) Bind(dialect internal.Dialect) *Creator {
+	c.dialect = dialect
+	return c
+}
+
+func (c *Creator) self() internal.Dialect {
+	if c.dialect != nil {
+		return c.dialect
+	}
+	return c
+}
+
 func (c *Creator) GetDriver() internal.Driver {
 	return c.driver
 }
@@ -61,187 +121,270 @@ func (c *Creator) Database() string {
 	return c.driver.Database()
 }
 
-func (*Creator) InsertExecutor(executor internal.Executor, command *internal.Command) (sql.Result, error) {
-	return nil, nil
+// Schema 首次调用时执行 Database 查询，成功后缓存结果，因此一个方言实例只应对应一个数据库
+func (c *Creator) Schema(ctx context.Context, executor internal.Executor) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.schema != "" {
+		return c.schema, nil
+	}
+	var schema sql.NullString
+	if err := executor.QueryRowContext(ctx, c.driver.Database()).Scan(&schema); err != nil {
+		return "", err
+	}
+	if schema.String == "" {
+		return "", fmt.Errorf("glue: no current schema selected for %s", c.driver.Name())
+	}
+	c.schema = schema.String
+	return c.schema, nil
 }
 
-func (*Creator) UpdateExecutor(executor internal.Executor, command *internal.Command) (sql.Result, error) {
-	return nil, nil
+// InsertExecutor 命令带有 RETURNING/OUTPUT 等子句时通过查询取回生成的主键，写入 command.Destination，否则直接执行
+func (*Creator) InsertExecutor(ctx context.Context, executor internal.Executor, command *internal.Command) (sql.Result, error) {
+	if command == nil {
+		return nil, errors.New("glue: nil command")
+	}
+	if command.GeneratedKey() == "" {
+		return executor.ExecContext(ctx, command.SQL(), command.Args()...)
+	}
+	dest := command.Destination()
+	if dest == nil {
+		dest = new(int64)
+	}
+	if err := executor.QueryRowContext(ctx, command.SQL(), command.Args()...).Scan(dest); err != nil {
+		return nil, err
+	}
+	return internal.KeyResult(dest, 1), nil
+}
+
+func (*Creator) UpdateExecutor(ctx context.Context, executor internal.Executor, command *internal.Command) (sql.Result, error) {
+	if command == nil {
+		return nil, errors.New("glue: nil command")
+	}
+	return executor.ExecContext(ctx, command.SQL(), command.Args()...)
 }
 
 func (*Creator) SQLType(field *reflect.StructField) string {
-	return ""
+	if field == nil {
+		return ""
+	}
+	// 通用实现只识别 type 标签，具体类型映射由各方言实现
+	tag, err := internal.ParseTag(field.Tag.Get(internal.TagName))
+	if err != nil {
+		return ""
+	}
+	return tag.Get(internal.TagType)
 }
 
-func (c *Creator) HasTable(name string) *internal.Command {
+func (c *Creator) HasTable(schema, name string) *internal.Command {
 	return internal.NewCommand("SELECT").
 		TabLine("COUNT(*)").
 		Line("FROM").
 		TabLine("INFORMATION_SCHEMA.TABLES").
 		Line("WHERE").
-		TabLine("table_schema = ?").
-		TabLine("AND table_name = ?").
-		Arguments(c.driver.Database(), name)
+		TabLine("table_schema = "+c.driver.Placeholder(1)).
+		TabLine("AND table_name = "+c.driver.Placeholder(2)).
+		Arguments(schema, c.fold(name))
 }
 
+// CreateTable 生成建表语句，索引和注释按方言要求生成为单独的语句
 func (c *Creator) CreateTable(definition *internal.Definition) []*internal.Command {
-	if definition == nil || definition.Fields == nil || len(definition.Fields) == 0 {
+	if definition == nil || len(definition.Fields) == 0 {
 		return nil
 	}
-	ln := len(definition.Fields)
-	// 一张表只允许一个 AUTO_INCREMENT 主键，需要标识判断
-	hasAutoIncrement := false
+	declared, declaration := DeclarationOf(c.self(), definition)
+	identity := IdentityOf(c.self(), definition)
+	inlinePrimary := false
+	comments := make([]*internal.Command, 0)
 
 	cmd := internal.NewCommand("CREATE TABLE").Space(c.driver.Quote(definition.TableName)).Space("(")
 	for idx, field := range definition.Fields {
-		cmd.TabLine(c.driver.Quote(field.Column)).Space(field.SQLType)
-		if field.NotNull {
-			cmd.Space("NOT")
+		if idx > 0 {
+			cmd.Append(",")
 		}
-		cmd.Space("NULL")
-		if field.IsPrimary {
-			stg := definition.Strategy
-			if !hasAutoIncrement && stg != nil && reflect.TypeOf(stg).Elem() == reflect.TypeOf(primary.AutoIncrement{}) {
-				// AutoIncrement
-				cmd.Space("AUTO_INCREMENT")
-				hasAutoIncrement = true
+		cmd.TabLine(c.driver.Quote(field.Column))
+		if id, ok := c.self().(Identifier); ok && field == identity {
+			column, inline := id.Identity(definition, field)
+			cmd.Space(column)
+			inlinePrimary = inline
+		} else {
+			cmd.Space(field.SQLType)
+			if field.NotNull {
+				cmd.Space("NOT")
+			}
+			cmd.Space("NULL")
+			if field == declared && declaration.Default != "" {
+				cmd.Space("DEFAULT").Space(declaration.Default)
+			} else if field.Default != nil {
+				cmd.Space("DEFAULT").Space(fmt.Sprintf("%v", field.Default))
 			}
 		}
-		if field.Default != nil {
-			cmd.Space("DEFAULT").Space(field.Default.(string))
-		}
-		if field.Comment != "" {
-			cmd.Space("COMMENT").Space(fmt.Sprintf("'%s'", field.Comment))
+		inline, comment := c.comment(definition.TableName, field.Column, field.Comment)
+		if inline != "" {
+			cmd.Space(inline)
 		}
-		if idx < ln-1 {
-			cmd.Append(",")
+		if comment != nil {
+			comments = append(comments, comment)
 		}
 	}
-	if len(definition.PrimaryKeys) > 0 {
-		// 有主键
-		keys := make([]string, 0)
-		for _, field := range definition.PrimaryKeys {
-			keys = append(keys, c.driver.Quote(field.Column))
-		}
-		cmd.Append(",").TabLine(fmt.Sprintf("PRIMARY KEY(%s)", strings.Join(keys, ", ")))
-	}
-	if len(definition.Indexes) > 0 {
-		// 有索引
-		for name, indexes := range definition.Indexes {
-			if indexes != nil && len(indexes) > 0 {
-				keys := make([]string, 0)
-				indexType := indexes[0].Type
-				for _, index := range indexes {
-					keys = append(keys, c.driver.Quote(index.Column))
-				}
-				cmd.Append(",")
-				switch indexType {
-				case internal.IndexUnique:
-					cmd.TabLine("UNIQUE ")
-					break
-				case internal.IndexFullText:
-					cmd.TabLine("FULLTEXT ")
-					break
-				case internal.IndexSpatial:
-					cmd.TabLine("SPATIAL ")
-					break
-				default:
-					cmd.TabLine("")
-					break
-				}
-				cmd.Append(fmt.Sprintf("INDEX %s (%s)", c.driver.Quote(name), strings.Join(keys, ", ")))
-			}
+	if len(definition.PrimaryKeys) > 0 && !inlinePrimary {
+		keys := make([]string, len(definition.PrimaryKeys))
+		for i, field := range definition.PrimaryKeys {
+			keys[i] = c.driver.Quote(field.Column)
 		}
+		cmd.Append(",").TabLine(fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(keys, ", ")))
 	}
-	if len(definition.ForeignKeys) > 0 {
-		// 有外键
-		for name, fks := range definition.ForeignKeys {
-			if fks != nil && len(fks) > 0 {
-				refs := make([]string, 0)
-				for _, index := range fks {
-					refs = append(refs, c.driver.Quote(index.Reference))
-				}
-				cmd.Append(",").TabLine(fmt.Sprintf("CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s)", name, c.driver.Quote(fks[0].Column), c.driver.Quote(fks[0].Table), strings.Join(refs, ", ")))
-			}
+	for _, name := range sortedKeys(definition.ForeignKeys) {
+		fks := definition.ForeignKeys[name]
+		columns := make([]string, len(fks))
+		references := make([]string, len(fks))
+		for i, fk := range fks {
+			columns[i] = c.driver.Quote(fk.Column)
+			references[i] = c.driver.Quote(fk.Reference)
 		}
+		cmd.Append(",").TabLine(fmt.Sprintf("CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s)", c.driver.Quote(name), strings.Join(columns, ", "), c.driver.Quote(fks[0].Table), strings.Join(references, ", ")))
 	}
 	cmd.Line(")")
 
-	return []*internal.Command{cmd}
+	commands := []*internal.Command{cmd}
+	if declaration != nil {
+		commands = append(append([]*internal.Command{}, declaration.Commands...), cmd)
+	}
+	for _, name := range sortedKeys(definition.Indexes) {
+		if index := c.self().AddIndex(definition.TableName, name, definition.Indexes[name]); index != nil {
+			commands = append(commands, index)
+		}
+	}
+	return append(commands, comments...)
 }
 
-func (c *Creator) Columns(table string) *internal.Command {
+func (c *Creator) Columns(schema, table string) *internal.Command {
 	return internal.NewCommand("SELECT").
 		TabLine("*").
 		Line("FROM").
 		TabLine("INFORMATION_SCHEMA.COLUMNS").
 		Line("WHERE").
-		TabLine("table_schema = ?").
-		TabLine("AND table_name = ?").
+		TabLine("table_schema = "+c.driver.Placeholder(1)).
+		TabLine("AND table_name = "+c.driver.Placeholder(2)).
 		Line("ORDER BY").
 		TabLine("ORDINAL_POSITION ASC").
-		Arguments(c.driver.Database(), table)
+		Arguments(schema, c.fold(table))
 }
 
-func (c *Creator) HasColumn(table, column string) *internal.Command {
+func (c *Creator) HasColumn(schema, table, column string) *internal.Command {
 	return internal.NewCommand("SELECT").
 		TabLine("COUNT(*)").
 		Line("FROM").
 		TabLine("INFORMATION_SCHEMA.COLUMNS").
 		Line("WHERE").
-		TabLine("table_schema = ?").
-		TabLine("AND table_name = ?").
-		TabLine("AND column_name = ?").
-		Arguments(c.driver.Database(), table, column)
+		TabLine("table_schema = "+c.driver.Placeholder(1)).
+		TabLine("AND table_name = "+c.driver.Placeholder(2)).
+		TabLine("AND column_name = "+c.driver.Placeholder(3)).
+		Arguments(schema, c.fold(table), c.fold(column))
 }
 
-func (c *Creator) ModifyColumn(table, column, rename, tpy, comment string, notNull bool, defValue interface{}) *internal.Command {
-	return internal.NewCommand(fmt.Sprintf("ALTER TABLE %v CHANGE COLUMN %v %v %v %v COMMENT '%v'", c.driver.Quote(table), c.driver.Quote(column), c.driver.Quote(rename), tpy, extraOfColumn(notNull, defValue), comment))
+// ModifyColumn 通用实现为 mysql 的 CHANGE COLUMN，其他方言各自覆盖
+func (c *Creator) ModifyColumn(table, column, rename, tpy, comment string, notNull bool, defValue interface{}) []*internal.Command {
+	if rename == "" {
+		rename = column
+	}
+	cmd := internal.NewCommand(fmt.Sprintf("ALTER TABLE %s CHANGE COLUMN %s %s %s", c.driver.Quote(table), c.driver.Quote(column), c.driver.Quote(rename), ColumnClause(tpy, notNull, defValue)))
+	return c.WithComment(cmd, table, rename, comment)
 }
 
-func (c *Creator) AddColumn(table, column, tpy, comment string, notNull bool, defValue interface{}) *internal.Command {
-	return internal.NewCommand(fmt.Sprintf("ALTER TABLE %v ADD COLUMN %v %v %v COMMENT '%v'", c.driver.Quote(table), c.driver.Quote(column), tpy, extraOfColumn(notNull, defValue), comment))
+func (c *Creator) AddColumn(table, column, tpy, comment string, notNull bool, defValue interface{}) []*internal.Command {
+	cmd := internal.NewCommand(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", c.driver.Quote(table), c.driver.Quote(column), ColumnClause(tpy, notNull, defValue)))
+	return c.WithComment(cmd, table, column, comment)
+}
+
+// WithComment 按 Commenter 为修改列的语句添加注释，内联的注释追加在 cmd 之后，其余作为单独的语句
+func (c *Creator) WithComment(cmd *internal.Command, table, column, comment string) []*internal.Command {
+	inline, command := c.comment(table, column, comment)
+	if inline != "" {
+		cmd.Space(inline)
+	}
+	if command == nil {
+		return []*internal.Command{cmd}
+	}
+	return []*internal.Command{cmd, command}
+}
+
+// comment 生成列注释，comment 为空时忽略，方言未实现 Commenter 时使用 COMMENT ON COLUMN
+func (c *Creator) comment(table, column, comment string) (string, *internal.Command) {
+	if comment == "" {
+		return "", nil
+	}
+	if commenter, ok := c.self().(Commenter); ok {
+		return commenter.Comment(table, &internal.Field{Column: column, Comment: comment})
+	}
+	return "", internal.NewCommand(fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s", c.driver.Quote(table), c.driver.Quote(column), c.self().Literal(comment)))
 }
 
 func (c *Creator) DropColumn(table, column string) *internal.Command {
 	return internal.NewCommand(fmt.Sprintf("ALTER TABLE %v DROP COLUMN %v", c.driver.Quote(table), c.driver.Quote(column)))
 }
 
-func (c *Creator) HasIndex(table, name string) *internal.Command {
+func (c *Creator) HasIndex(schema, table, name string) *internal.Command {
 	return internal.NewCommand("SELECT").
 		TabLine("COUNT(*)").
 		Line("FROM").
 		TabLine("INFORMATION_SCHEMA.STATISTICS").
 		Line("WHERE").
-		TabLine("table_schema = ?").
-		TabLine("AND table_schema = ?").
-		TabLine("AND index_name = ?").
-		Arguments(c.driver.Database(), c.driver.Quote(table), c.driver.Quote(name))
+		TabLine("table_schema = "+c.driver.Placeholder(1)).
+		TabLine("AND table_name = "+c.driver.Placeholder(2)).
+		TabLine("AND index_name = "+c.driver.Placeholder(3)).
+		Arguments(schema, c.fold(table), c.fold(name))
+}
+
+// AddIndex 创建索引，indexes 为同名索引的各列，通用实现不支持 FULLTEXT 和 SPATIAL，按普通索引创建
+func (c *Creator) AddIndex(table, name string, indexes []*internal.Index) *internal.Command {
+	if len(indexes) == 0 {
+		return nil
+	}
+	columns := make([]string, len(indexes))
+	for i, index := range indexes {
+		columns[i] = c.driver.Quote(index.Column)
+	}
+	kind := "INDEX"
+	if indexes[0].Type == internal.IndexUnique {
+		kind = "UNIQUE INDEX"
+	}
+	return internal.NewCommand(fmt.Sprintf("CREATE %s %s ON %s (%s)", kind, c.driver.Quote(name), c.driver.Quote(table), strings.Join(columns, ", ")))
 }
 
 func (c *Creator) RemoveIndex(table, name string) *internal.Command {
 	return internal.NewCommand(fmt.Sprintf("DROP INDEX %v", c.driver.Quote(name)))
 }
 
-func (c *Creator) HasForeignKey(table, name string) *internal.Command {
+func (c *Creator) HasForeignKey(schema, table, name string) *internal.Command {
 	return internal.NewCommand("SELECT").
 		TabLine("COUNT(*)").
 		Line("FROM").
 		TabLine("INFORMATION_SCHEMA.TABLE_CONSTRAINTS").
 		Line("WHERE").
-		TabLine("CONSTRAINT_SCHEMA = ?").
-		TabLine("AND TABLE_NAME = ?").
-		TabLine("AND CONSTRAINT_NAME = ?").
+		TabLine("CONSTRAINT_SCHEMA = "+c.driver.Placeholder(1)).
+		TabLine("AND TABLE_NAME = "+c.driver.Placeholder(2)).
+		TabLine("AND CONSTRAINT_NAME = "+c.driver.Placeholder(3)).
 		TabLine("AND CONSTRAINT_TYPE = 'FOREIGN KEY'").
-		Arguments(c.driver.Database(), table, name)
+		Arguments(schema, c.fold(table), c.fold(name))
 }
 
+// AddForeignKey 复合外键的 Column 和 Reference 为以逗号分隔的多个列，如 migrate 合并后的 "a, b"
 func (c *Creator) AddForeignKey(table string, key *internal.ForeignKey) *internal.Command {
-	return internal.NewCommand(fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s(%s)", c.driver.Quote(table), key.Name, key.Column, c.driver.Quote(key.Table), key.Reference))
+	return internal.NewCommand(fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s)", c.driver.Quote(table), c.driver.Quote(key.Name), c.quoteColumns(key.Column), c.driver.Quote(key.Table), c.quoteColumns(key.Reference)))
+}
+
+// quoteColumns 逐个引用以逗号分隔的列名
+func (c *Creator) quoteColumns(columns string) string {
+	names := strings.Split(columns, ",")
+	for i, name := range names {
+		names[i] = c.driver.Quote(strings.TrimSpace(name))
+	}
+	return strings.Join(names, ", ")
 }
 
 func (c *Creator) RemoveForeignKey(table, name string) *internal.Command {
-	return internal.NewCommand(fmt.Sprintf("ALTER TABLE %s DROP FOREIGN KEY (%s)", c.driver.Quote(table), name))
+	return internal.NewCommand(fmt.Sprintf("ALTER TABLE %s DROP FOREIGN KEY %s", c.driver.Quote(table), c.driver.Quote(name)))
 }
 
 func (*Creator) DefaultValue() string {
@@ -252,58 +395,317 @@ func (*Creator) BuildKeyName(kind, table string, fields ...string) string {
 	return fmt.Sprintf("%s_%s,%s", kind, table, strings.Join(fields, "_"))
 }
 
+// Insert 值为 internal.Expression 时直接写入表达式，表达式无法生成时返回 nil
 func (c *Creator) Insert(value *internal.ExecValue) *internal.Command {
-	if value == nil {
+	if !value.Valid() {
 		return nil
 	}
-	return internal.NewCommand(fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", c.driver.Quote(value.Table), )).Arguments(value.Values...)
+	columns := make([]string, len(value.Columns))
+	holders := make([]string, len(value.Columns))
+	args := make([]interface{}, 0, len(value.Values))
+	for i, column := range value.Columns {
+		columns[i] = c.driver.Quote(column)
+		if expr, ok := value.Values[i].(internal.Expression); ok {
+			holder, err := expr.Expression(c.self())
+			if err != nil {
+				return nil
+			}
+			holders[i] = holder
+			continue
+		}
+		args = append(args, value.Values[i])
+		holders[i] = c.driver.Placeholder(len(args))
+	}
+	output, suffix := "", ""
+	if rt, ok := c.self().(Returner); ok && value.Primary != "" {
+		output, suffix = rt.Returning(value.Primary, len(args)+1)
+	}
+
+	cmd := internal.NewCommand("INSERT INTO").Space(c.driver.Quote(value.Table))
+	if len(value.Columns) > 0 {
+		cmd.Space(fmt.Sprintf("(%s)", strings.Join(columns, ", ")))
+		if output != "" {
+			cmd.Space(output)
+		}
+		cmd.Space(fmt.Sprintf("VALUES (%s)", strings.Join(holders, ", ")))
+	} else {
+		if output != "" {
+			cmd.Space(output)
+		}
+		cmd.Space(c.self().DefaultValue())
+	}
+	if suffix != "" {
+		cmd.Space(suffix)
+	}
+	if output != "" || suffix != "" {
+		cmd.Generated(value.Primary)
+	}
+	return cmd.Arguments(args...)
 }
 
-func (*Creator) Delete(value *internal.ExecValue) *internal.Command {
-	return nil
+// Delete 物理删除，Columns 与 Values 作为等值条件，与 Where 同时生效
+func (c *Creator) Delete(value *internal.ExecValue) *internal.Command {
+	if !value.Valid() {
+		return nil
+	}
+	conditions := make([]internal.Condition, 0, len(value.Columns)+1)
+	for i, column := range value.Columns {
+		conditions = append(conditions, internal.Eq(column, value.Values[i]))
+	}
+	conditions = append(conditions, value.Where)
+	return c.where(internal.NewCommand(fmt.Sprintf("DELETE FROM %s", c.driver.Quote(value.Table))), internal.And(conditions...))
 }
 
-func (*Creator) Remove(value *internal.ExecValue) *internal.Command {
-	return nil
+// Remove 逻辑删除，将 Columns 更新为 Values 中的删除标记
+func (c *Creator) Remove(value *internal.ExecValue) *internal.Command {
+	if !value.Valid() || len(value.Columns) == 0 {
+		return nil
+	}
+	return c.update(value)
 }
 
-func (*Creator) Update(value *internal.ExecValue) *internal.Command {
-	return nil
+func (c *Creator) Update(value *internal.ExecValue) *internal.Command {
+	if !value.Valid() || len(value.Columns) == 0 {
+		return nil
+	}
+	return c.update(value)
 }
 
-func (*Creator) Select(value *internal.ExecValue) *internal.Command {
-	return nil
+func (c *Creator) Select(value *internal.ExecValue) *internal.Command {
+	if value == nil {
+		return nil
+	}
+	columns := "*"
+	if len(value.Columns) > 0 {
+		quoted := make([]string, len(value.Columns))
+		for i, column := range value.Columns {
+			quoted[i] = c.driver.Quote(column)
+		}
+		columns = strings.Join(quoted, ", ")
+	}
+	return c.where(internal.NewCommand(fmt.Sprintf("SELECT %s FROM %s", columns, c.driver.Quote(value.Table))), value.Where)
 }
 
 func (*Creator) Count(cmd *internal.Command) *internal.Command {
-	return internal.NewCommand(fmt.Sprintf("SELECT COUNT(*) FROM (%s) AS T", cmd.SQL())).Arguments(cmd.Args()...)
+	// 子查询别名不使用 AS，oracle 不支持
+	return internal.NewCommand(fmt.Sprintf("SELECT COUNT(*) FROM (%s) T", cmd.SQL())).Arguments(cmd.Args()...)
 }
 
+// Page 页码从 1 开始，小于 1 时按第 1 页处理
 func (c *Creator) Page(cmd *internal.Command, page, size int) *internal.Command {
-	if page <= 0 {
-		page = 0
+	if page < 1 {
+		page = 1
 	}
-	offset := (page - 1) * size
-	args := append(cmd.Args(), offset, size)
-	return internal.NewCommand(fmt.Sprintf("SELECT * FROM (%s) AS T LIMIT ?, ?", cmd.SQL())).Arguments(args...)
+	return c.self().Limit(cmd, size, (page-1)*size)
 }
 
-func extraOfColumn(notNull bool, defValue interface{}) (result string) {
-	if notNull {
-		result += "NOT "
+// Limit 在 cmd 末尾追加 LIMIT/OFFSET，limit <= 0 表示不限制条数，offset <= 0 表示不跳过
+func (c *Creator) Limit(cmd *internal.Command, limit, offset int) *internal.Command {
+	result := cmd.Clone()
+	if limit > 0 {
+		result.Line("LIMIT " + c.driver.Placeholder(len(result.Args())+1)).Arguments(limit)
+	}
+	if offset > 0 {
+		result.Line("OFFSET " + c.driver.Placeholder(len(result.Args())+1)).Arguments(offset)
 	}
-	result += "NULL"
+	return result
+}
 
-	if defValue != nil {
-		result += " DEFAULT "
-		tp := reflect.ValueOf(defValue)
-		value := fmt.Sprintf("%v", defValue)
-		if tp.Kind() == reflect.String {
-			result += fmt.Sprintf("'%v'", value)
-		} else {
-			result += value
+// Seek 游标分页，查询排在 cursor 之后的 size 条记录，cursor 为 nil 时查询第一页，
+// cursor 通常来自客户端，值的个数与 keys 不一致时返回错误。
+// cmd 作为子查询 T 包装后再按 keys 过滤和排序，因此 keys 的列必须出现在 cmd 的查询列中，
+// cmd 自身的 ORDER BY 不影响结果，mssql 的子查询不允许单独使用 ORDER BY
+func (c *Creator) Seek(cmd *internal.Command, keys []*internal.Key, cursor *internal.Cursor, size int) (*internal.Command, error) {
+	result := internal.NewCommand("SELECT * FROM (").Line(cmd.SQL()).Line(") T").Arguments(cmd.Args()...)
+	if len(keys) == 0 {
+		return c.self().Limit(result, size, 0), nil
+	}
+	if cursor != nil {
+		if len(cursor.Values) != len(keys) {
+			return nil, fmt.Errorf("glue: cursor has %d values, expected %d", len(cursor.Values), len(keys))
+		}
+		sql, args := c.seekCondition(keys, cursor.Values, len(result.Args())+1)
+		result.Line("WHERE").TabLine(sql).Arguments(args...)
+	}
+	orders := make([]string, len(keys))
+	for i, key := range keys {
+		orders[i] = c.seekColumn(key)
+		if key.Desc {
+			orders[i] += " DESC"
+		}
+	}
+	result.Line("ORDER BY").TabLine(strings.Join(orders, ", "))
+	return c.self().Limit(result, size, 0), nil
+}
+
+// seekCondition 排序方向一致且方言支持行值比较时生成 (a, b) > (?, ?)，
+// 否则展开为 a > ? OR (a = ? AND b > ?)
+func (c *Creator) seekCondition(keys []*internal.Key, values []interface{}, start int) (string, []interface{}) {
+	sameDirection := true
+	for _, key := range keys {
+		sameDirection = sameDirection && key.Desc == keys[0].Desc
+	}
+	if rc, ok := c.self().(RowComparer); ok && rc.RowComparison() && sameDirection && len(keys) > 1 {
+		columns := make([]string, len(keys))
+		holders := make([]string, len(keys))
+		for i, key := range keys {
+			columns[i] = c.seekColumn(key)
+			holders[i] = c.driver.Placeholder(start + i)
+		}
+		return fmt.Sprintf("(%s) %s (%s)", strings.Join(columns, ", "), seekOperator(keys[0]), strings.Join(holders, ", ")), values
+	}
+
+	ors := make([]string, len(keys))
+	args := make([]interface{}, 0)
+	for i, key := range keys {
+		ands := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			ands = append(ands, fmt.Sprintf("%s = %s", c.seekColumn(keys[j]), c.driver.Placeholder(start+len(args))))
+			args = append(args, values[j])
+		}
+		ands = append(ands, fmt.Sprintf("%s %s %s", c.seekColumn(key), seekOperator(key), c.driver.Placeholder(start+len(args))))
+		args = append(args, values[i])
+		ors[i] = strings.Join(ands, " AND ")
+		if len(keys) > 1 {
+			ors[i] = "(" + ors[i] + ")"
+		}
+	}
+	return strings.Join(ors, " OR "), args
+}
+
+// seekColumn 用子查询别名限定列名，列不在子查询中时由数据库报错，sqlite 不会将其当作字符串
+func (c *Creator) seekColumn(key *internal.Key) string {
+	return "T." + c.driver.Quote(key.Column)
+}
+
+func (c *Creator) update(value *internal.ExecValue) *internal.Command {
+	cmd := internal.NewCommand(fmt.Sprintf("UPDATE %s SET %s", c.driver.Quote(value.Table), strings.Join(c.assignments(value.Columns, 1), ", "))).Arguments(value.Values...)
+	return c.where(cmd, value.Where)
+}
+
+// Savepoint 保存点名称由调用方生成，不加引号
+func (*Creator) Savepoint(name string) *internal.Command {
+	return internal.NewCommand("SAVEPOINT " + name)
+}
+
+func (*Creator) RollbackTo(name string) *internal.Command {
+	return internal.NewCommand("ROLLBACK TO SAVEPOINT " + name)
+}
+
+func (*Creator) ReleaseSavepoint(name string) *internal.Command {
+	return internal.NewCommand("RELEASE SAVEPOINT " + name)
+}
+
+// fold 目录查询中的名称参数按驱动的折叠规则转换，与数据字典中保存的名称一致
+func (c *Creator) fold(name string) string {
+	if folder, ok := c.driver.(Folder); ok {
+		return folder.Fold(name)
+	}
+	return name
+}
+
+// where 追加 WHERE 子句，占位符接着 cmd 已有的参数编号
+func (c *Creator) where(cmd *internal.Command, condition internal.Condition) *internal.Command {
+	if condition == nil {
+		return cmd
+	}
+	sql, args := condition.Build(c.driver, len(cmd.Args())+1)
+	if sql == "" {
+		return cmd
+	}
+	return cmd.Space("WHERE").Space(sql).Arguments(args...)
+}
+
+// assignments 生成 `column = placeholder` 列表，占位符序号从 start 开始
+func (c *Creator) assignments(columns []string, start int) []string {
+	result := make([]string, len(columns))
+	for i, column := range columns {
+		result[i] = fmt.Sprintf("%s = %s", c.driver.Quote(column), c.driver.Placeholder(start+i))
+	}
+	return result
+}
+
+// HasOrderBy 判断 sql 最外层是否已有 ORDER BY，忽略括号和引号中的内容
+func HasOrderBy(sql string) bool {
+	depth := 0
+	var quote rune
+	upper := []rune(strings.ToUpper(sql))
+	for i, r := range upper {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case depth == 0 && r == 'O' && strings.HasPrefix(string(upper[i:]), "ORDER"):
+			rest := strings.TrimLeft(string(upper[i+5:]), " \t\r\n")
+			if strings.HasPrefix(rest, "BY") && (i == 0 || strings.ContainsRune(" \t\r\n)", upper[i-1])) {
+				return true
+			}
 		}
 	}
+	return false
+}
+
+func seekOperator(key *internal.Key) string {
+	if key.Desc {
+		return "<"
+	}
+	return ">"
+}
 
-	return
+// DeclarationOf 返回主键策略在 dialect 下声明的主键列，只有第一个主键受策略影响，
+// 策略未实现 internal.Declarer 或未作声明时返回 nil
+func DeclarationOf(dialect internal.Dialect, definition *internal.Definition) (*internal.Field, *internal.Declaration) {
+	declarer, ok := definition.Strategy.(internal.Declarer)
+	if !ok || len(definition.PrimaryKeys) == 0 {
+		return nil, nil
+	}
+	declaration := declarer.Declare(dialect)
+	if declaration == nil {
+		return nil, nil
+	}
+	for _, field := range definition.Fields {
+		if field.Column == definition.PrimaryKeys[0].Column {
+			return field, declaration
+		}
+	}
+	return nil, nil
+}
+
+// IdentityOf 返回由数据库自增生成的主键列
+func IdentityOf(dialect internal.Dialect, definition *internal.Definition) *internal.Field {
+	field, declaration := DeclarationOf(dialect, definition)
+	if declaration == nil || !declaration.Identity {
+		return nil
+	}
+	return field
+}
+
+func sortedKeys(m interface{}) []string {
+	keys := reflect.ValueOf(m).MapKeys()
+	result := make([]string, len(keys))
+	for i, key := range keys {
+		result[i] = key.String()
+	}
+	sort.Strings(result)
+	return result
+}
+
+// ColumnClause 列名之后的类型、默认值和可空性，默认值与 CreateTable 一致按原样写入，
+// DEFAULT 写在 NULL 之前，oracle 只接受这种顺序
+func ColumnClause(tpy string, notNull bool, defValue interface{}) string {
+	result := tpy
+	if defValue != nil {
+		result += fmt.Sprintf(" DEFAULT %v", defValue)
+	}
+	if notNull {
+		return result + " NOT NULL"
+	}
+	return result + " NULL"
 }
@@ -24,7 +24,9 @@ import (
 	"fmt"
 	"github.com/yhyzgn/glue/internal"
 	"github.com/yhyzgn/glue/primary"
+	"reflect"
 	"testing"
+	"time"
 )
 
 func TestDefault_CreateTable(t *testing.T) {
@@ -117,9 +119,150 @@ func TestDefault_CreateTable(t *testing.T) {
 		ForeignKeys: map[string][]*internal.ForeignKey{},
 	}
 
-	dft := Creator{}
+	dft := New(new(testDriver))
 
 	cmd := dft.CreateTable(dfs)
 
 	fmt.Println(cmd[0].SQL())
 }
+
+type testDriver struct{}
+
+func (*testDriver) Name() string {
+	return "test"
+}
+
+func (*testDriver) Driver() string {
+	return "test"
+}
+
+func (*testDriver) Quote(key string) string {
+	return fmt.Sprintf(`"%s"`, key)
+}
+
+func (*testDriver) Placeholder(index int) string {
+	return fmt.Sprintf("$%d", index)
+}
+
+func (*testDriver) Database() string {
+	return "SELECT DATABASE()"
+}
+
+func TestCreator_Exec(t *testing.T) {
+	dft := New(new(testDriver))
+
+	cases := []struct {
+		cmd  *internal.Command
+		sql  string
+		args []interface{}
+	}{
+		{
+			cmd:  dft.Insert(&internal.ExecValue{Table: "user", Columns: []string{"name", "age"}, Values: []interface{}{"glue", 18}}),
+			sql:  `INSERT INTO "user" ("name", "age") VALUES ($1, $2)`,
+			args: []interface{}{"glue", 18},
+		},
+		{
+			cmd:  dft.Insert(&internal.ExecValue{Table: "user"}),
+			sql:  `INSERT INTO "user" DEFAULT VALUES`,
+			args: []interface{}{},
+		},
+		{
+			cmd:  dft.Update(&internal.ExecValue{Table: "user", Columns: []string{"name", "age"}, Values: []interface{}{"glue", 18}}),
+			sql:  `UPDATE "user" SET "name" = $1, "age" = $2`,
+			args: []interface{}{"glue", 18},
+		},
+		{
+			cmd:  dft.Remove(&internal.ExecValue{Table: "user", Columns: []string{"deleted"}, Values: []interface{}{1}}),
+			sql:  `UPDATE "user" SET "deleted" = $1`,
+			args: []interface{}{1},
+		},
+		{
+			cmd:  dft.Delete(&internal.ExecValue{Table: "user", Columns: []string{"id", "code"}, Values: []interface{}{1, "a"}}),
+			sql:  `DELETE FROM "user" WHERE "id" = $1 AND "code" = $2`,
+			args: []interface{}{1, "a"},
+		},
+		{
+			cmd:  dft.Select(&internal.ExecValue{Table: "user", Columns: []string{"id", "name"}}),
+			sql:  `SELECT "id", "name" FROM "user"`,
+			args: []interface{}{},
+		},
+		{
+			cmd:  dft.Update(&internal.ExecValue{Table: "user", Columns: []string{"name"}, Values: []interface{}{"glue"}, Where: internal.Eq("id", 1)}),
+			sql:  `UPDATE "user" SET "name" = $1 WHERE "id" = $2`,
+			args: []interface{}{"glue", 1},
+		},
+		{
+			cmd:  dft.Delete(&internal.ExecValue{Table: "user", Columns: []string{"code"}, Values: []interface{}{"a"}, Where: internal.Or(internal.Lt("age", 18), internal.IsNull("age"))}),
+			sql:  `DELETE FROM "user" WHERE "code" = $1 AND ("age" < $2 OR "age" IS NULL)`,
+			args: []interface{}{"a", 18},
+		},
+		{
+			cmd:  dft.Select(&internal.ExecValue{Table: "user", Where: internal.In("id", 1, 2)}),
+			sql:  `SELECT * FROM "user" WHERE "id" IN ($1, $2)`,
+			args: []interface{}{1, 2},
+		},
+	}
+
+	for _, cs := range cases {
+		if cs.cmd.SQL() != cs.sql {
+			t.Errorf("unexpected sql: %s, expected: %s", cs.cmd.SQL(), cs.sql)
+		}
+		if !reflect.DeepEqual(cs.cmd.Args(), cs.args) {
+			t.Errorf("unexpected args: %v, expected: %v", cs.cmd.Args(), cs.args)
+		}
+	}
+
+	// 列与值数量不一致时不生成语句
+	mismatched := &internal.ExecValue{Table: "user", Columns: []string{"id", "code"}, Values: []interface{}{1}}
+	for _, cmd := range []*internal.Command{dft.Insert(mismatched), dft.Update(mismatched), dft.Remove(mismatched), dft.Delete(mismatched)} {
+		if cmd != nil {
+			t.Errorf("unexpected command of mismatched value: %s", cmd.SQL())
+		}
+	}
+}
+
+func TestHasOrderBy(t *testing.T) {
+	cases := map[string]bool{
+		"SELECT * FROM t ORDER BY id":                                true,
+		"SELECT * FROM t\nORDER\tBY id":                              true,
+		"SELECT * FROM (SELECT * FROM t ORDER BY id) T":              false,
+		"SELECT 'ORDER BY' AS label FROM t":                          false,
+		"SELECT border_by FROM t":                                    false,
+		"SELECT * FROM t WHERE id IN (SELECT id FROM s) ORDER BY id": true,
+	}
+	for sql, expected := range cases {
+		if HasOrderBy(sql) != expected {
+			t.Errorf("HasOrderBy(%q) should be %v", sql, expected)
+		}
+	}
+}
+
+func TestCreator_Literal(t *testing.T) {
+	dft := New(new(testDriver))
+	name := "it's"
+	cmd := internal.NewCommand(`UPDATE "t" SET "name" = $1, "note" = '$2', "data" = $2, "at" = $3, "ok" = $4, "n" = $5 WHERE "id" IN ($6, $7, $8, $9, $10)`).
+		Arguments(&name, []byte{0xde, 0xad}, time.Date(2020, 1, 28, 10, 5, 0, 0, time.UTC), true, nil, 1, 2, 3, 4, 1.5)
+	expected := `UPDATE "t" SET "name" = 'it''s', "note" = '$2', "data" = X'dead', "at" = '2020-01-28 10:05:00', "ok" = TRUE, "n" = NULL WHERE "id" IN (1, 2, 3, 4, 1.5)`
+	if sql := internal.Inline(cmd, dft, dft.Literal); sql != expected {
+		t.Errorf("unexpected sql:\n%s\nexpected:\n%s", sql, expected)
+	}
+
+	// 方括号只在用它引用标识符的方言中视为引号
+	cmd = internal.NewCommand(`SELECT "tags"[$1], ARRAY[$2, $3] FROM "t"`).Arguments(1, "a", "b")
+	if sql := internal.Inline(cmd, dft, dft.Literal); sql != `SELECT "tags"[1], ARRAY['a', 'b'] FROM "t"` {
+		t.Errorf("unexpected array sql: %s", sql)
+	}
+	bracket := New(new(bracketDriver))
+	cmd = internal.NewCommand(`SELECT [note $1] FROM [t] WHERE [id] = $1`).Arguments(7)
+	if sql := internal.Inline(cmd, bracket, bracket.Literal); sql != `SELECT [note $1] FROM [t] WHERE [id] = 7` {
+		t.Errorf("unexpected bracket sql: %s", sql)
+	}
+}
+
+type bracketDriver struct {
+	testDriver
+}
+
+func (*bracketDriver) Quote(key string) string {
+	return "[" + key + "]"
+}
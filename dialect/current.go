@@ -22,4 +22,7 @@ package dialect
 
 import "github.com/yhyzgn/glue/internal"
 
+// Current 未显式指定方言时使用的默认方言，如 builder.Select，方言构造函数不会修改它，需要时由使用方设置
+//
+// 同时使用多种数据库时应显式传入方言，或通过 Lookup、FromDB 获取
 var Current internal.Dialect
@@ -0,0 +1,87 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-02-22 09:45
+// version: 1.0.0
+// desc   : 
+
+package dialect
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/yhyzgn/glue/internal"
+	"regexp"
+	"strings"
+)
+
+// ErrorParser 方言从驱动的原生错误中解析出分类及约束、表、列名，无法识别时返回 nil
+type ErrorParser interface {
+	ParseError(err error) *internal.Error
+}
+
+// Classify 未实现 ErrorParser 的方言只识别 sql.ErrNoRows
+func (c *Creator) Classify(err error) internal.ErrorKind {
+	if e := c.parse(err); e != nil {
+		return e.Kind
+	}
+	return internal.KindUnknown
+}
+
+// Translate 已经转换过的错误原样返回，可以重复调用
+func (c *Creator) Translate(err error) error {
+	if e := c.parse(err); e != nil {
+		return e
+	}
+	return err
+}
+
+func (c *Creator) parse(err error) *internal.Error {
+	if err == nil {
+		return nil
+	}
+	var translated *internal.Error
+	if errors.As(err, &translated) {
+		return translated
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return &internal.Error{Kind: internal.KindNoRows, Err: err}
+	}
+	if parser, ok := c.self().(ErrorParser); ok {
+		return parser.ParseError(err)
+	}
+	return nil
+}
+
+// Unqualify 去掉名称中的 schema 或表名前缀及引号，如 "app"."uk_user" 得到 uk_user
+func Unqualify(name string) string {
+	name = strings.Trim(name, "`\"[]' ")
+	if idx := strings.LastIndex(name, "."); idx >= 0 {
+		name = name[idx+1:]
+	}
+	return strings.Trim(name, "`\"[]' ")
+}
+
+// Submatch 返回 re 在 s 中第一个非空的分组，用于从错误信息中提取约束名等
+func Submatch(re *regexp.Regexp, s string) string {
+	match := re.FindStringSubmatch(s)
+	for i := 1; i < len(match); i++ {
+		if match[i] != "" {
+			return match[i]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,237 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-01-30 10:15
+// version: 1.0.0
+// desc   : 
+
+package dialect
+
+import (
+	"context"
+	"database/sql"
+	"github.com/yhyzgn/glue/internal"
+	"strings"
+)
+
+// Catalog 读取表结构的查询，各查询结果列的别名约定如下：
+//
+//	TablesQuery      name
+//	ColumnsQuery     name, type, nullable, dflt, remarks, ordinal, is_identity
+//	PrimaryKeysQuery name，按主键中的顺序
+//	IndexesQuery     name, column_name, is_unique, kind，按索引名和列顺序，kind 为 FULLTEXT 或 SPATIAL，可省略
+//	ForeignKeysQuery name, column_name, ref_table, ref_column，按外键名和列顺序
+type Catalog interface {
+	TablesQuery() *internal.Command
+	ColumnsQuery(table string) *internal.Command
+	PrimaryKeysQuery(table string) *internal.Command
+	IndexesQuery(table string) *internal.Command
+	ForeignKeysQuery(table string) *internal.Command
+}
+
+type columnRow struct {
+	Name     string         `glue:"column:name"`
+	Type     string         `glue:"column:type"`
+	Nullable bool           `glue:"column:nullable"`
+	Default  sql.NullString `glue:"column:dflt"`
+	Comment  sql.NullString `glue:"column:remarks"`
+	Ordinal  int            `glue:"column:ordinal"`
+	Identity bool           `glue:"column:is_identity"`
+}
+
+type indexRow struct {
+	Name   string         `glue:"column:name"`
+	Column string         `glue:"column:column_name"`
+	Unique bool           `glue:"column:is_unique"`
+	Kind   sql.NullString `glue:"column:kind"`
+}
+
+type foreignKeyRow struct {
+	Name      string `glue:"column:name"`
+	Column    string `glue:"column:column_name"`
+	Table     string `glue:"column:ref_table"`
+	Reference string `glue:"column:ref_column"`
+}
+
+// 以下查询适用于 mysql 的 information_schema，其他方言各自覆盖
+
+func (c *Creator) TablesQuery() *internal.Command {
+	return internal.NewCommand("SELECT table_name AS name FROM information_schema.tables").
+		Line("WHERE table_schema = DATABASE() AND table_type = 'BASE TABLE'").
+		Line("ORDER BY table_name")
+}
+
+func (c *Creator) ColumnsQuery(table string) *internal.Command {
+	return internal.NewCommand("SELECT column_name AS name, column_type AS type, CASE WHEN is_nullable = 'YES' THEN 1 ELSE 0 END AS nullable,").
+		TabLine("column_default AS dflt, column_comment AS remarks, ordinal_position AS ordinal,").
+		TabLine("CASE WHEN extra LIKE '%auto_increment%' THEN 1 ELSE 0 END AS is_identity").
+		Line("FROM information_schema.columns").
+		Line("WHERE table_schema = DATABASE() AND table_name = " + c.driver.Placeholder(1)).
+		Line("ORDER BY ordinal_position").
+		Arguments(table)
+}
+
+func (c *Creator) PrimaryKeysQuery(table string) *internal.Command {
+	return internal.NewCommand("SELECT column_name AS name FROM information_schema.key_column_usage").
+		Line("WHERE table_schema = DATABASE() AND table_name = " + c.driver.Placeholder(1) + " AND constraint_name = 'PRIMARY'").
+		Line("ORDER BY ordinal_position").
+		Arguments(table)
+}
+
+func (c *Creator) IndexesQuery(table string) *internal.Command {
+	return internal.NewCommand("SELECT index_name AS name, column_name, CASE WHEN non_unique = 0 THEN 1 ELSE 0 END AS is_unique, index_type AS kind").
+		Line("FROM information_schema.statistics").
+		Line("WHERE table_schema = DATABASE() AND table_name = " + c.driver.Placeholder(1) + " AND index_name <> 'PRIMARY'").
+		Line("ORDER BY index_name, seq_in_index").
+		Arguments(table)
+}
+
+func (c *Creator) ForeignKeysQuery(table string) *internal.Command {
+	return internal.NewCommand("SELECT constraint_name AS name, column_name, referenced_table_name AS ref_table, referenced_column_name AS ref_column").
+		Line("FROM information_schema.key_column_usage").
+		Line("WHERE table_schema = DATABASE() AND table_name = " + c.driver.Placeholder(1) + " AND referenced_table_name IS NOT NULL").
+		Line("ORDER BY constraint_name, ordinal_position").
+		Arguments(table)
+}
+
+// Tables 当前库中所有表的表名
+func (c *Creator) Tables(ctx context.Context, executor internal.Executor) ([]string, error) {
+	rows := make([]*struct {
+		Name string `glue:"column:name"`
+	}, 0)
+	if err := query(ctx, executor, c.catalog().TablesQuery(), &rows); err != nil {
+		return nil, err
+	}
+	tables := make([]string, len(rows))
+	for i, row := range rows {
+		tables[i] = row.Name
+	}
+	return tables, nil
+}
+
+// Inspect 读取表的列、主键、索引和外键，表不存在时返回 nil
+func (c *Creator) Inspect(ctx context.Context, executor internal.Executor, table string) (*internal.TableInfo, error) {
+	catalog := c.catalog()
+	columns := make([]*columnRow, 0)
+	if err := query(ctx, executor, catalog.ColumnsQuery(table), &columns); err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return nil, nil
+	}
+	info := &internal.TableInfo{
+		Name:        table,
+		Columns:     make([]*internal.ColumnInfo, len(columns)),
+		PrimaryKeys: make([]string, 0),
+		Indexes:     make([]*internal.IndexInfo, 0),
+		ForeignKeys: make([]*internal.ForeignKeyInfo, 0),
+	}
+	for i, row := range columns {
+		column := &internal.ColumnInfo{
+			Name:     row.Name,
+			Type:     row.Type,
+			Nullable: row.Nullable,
+			Comment:  row.Comment.String,
+			Ordinal:  row.Ordinal,
+			Identity: row.Identity,
+		}
+		if row.Default.Valid {
+			value := row.Default.String
+			column.Default = &value
+		}
+		info.Columns[i] = column
+	}
+
+	keys := make([]*struct {
+		Name string `glue:"column:name"`
+	}, 0)
+	if err := query(ctx, executor, catalog.PrimaryKeysQuery(table), &keys); err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		info.PrimaryKeys = append(info.PrimaryKeys, key.Name)
+	}
+
+	indexes := make([]*indexRow, 0)
+	if err := query(ctx, executor, catalog.IndexesQuery(table), &indexes); err != nil {
+		return nil, err
+	}
+	for _, row := range indexes {
+		last := len(info.Indexes) - 1
+		if last < 0 || info.Indexes[last].Name != row.Name {
+			index := &internal.IndexInfo{Name: row.Name, Type: internal.IndexNormal}
+			switch {
+			case strings.EqualFold(row.Kind.String, "FULLTEXT"):
+				index.Type = internal.IndexFullText
+			case strings.EqualFold(row.Kind.String, "SPATIAL"):
+				index.Type = internal.IndexSpatial
+			case row.Unique:
+				index.Type = internal.IndexUnique
+			}
+			info.Indexes = append(info.Indexes, index)
+			last++
+		}
+		info.Indexes[last].Columns = append(info.Indexes[last].Columns, row.Column)
+	}
+
+	fks := make([]*foreignKeyRow, 0)
+	if err := query(ctx, executor, catalog.ForeignKeysQuery(table), &fks); err != nil {
+		return nil, err
+	}
+	for _, row := range fks {
+		last := len(info.ForeignKeys) - 1
+		if last < 0 || info.ForeignKeys[last].Name != row.Name {
+			info.ForeignKeys = append(info.ForeignKeys, &internal.ForeignKeyInfo{Name: row.Name, Table: row.Table})
+			last++
+		}
+		info.ForeignKeys[last].Columns = append(info.ForeignKeys[last].Columns, row.Column)
+		info.ForeignKeys[last].References = append(info.ForeignKeys[last].References, row.Reference)
+	}
+	return info, nil
+}
+
+func (c *Creator) catalog() Catalog {
+	if catalog, ok := c.self().(Catalog); ok {
+		return catalog
+	}
+	return c
+}
+
+// Exists 解析当前 schema 后执行 build 生成的计数查询，如 HasTable、HasColumn，结果大于 0 时返回 true
+func Exists(ctx context.Context, dialect internal.Dialect, executor internal.Executor, build func(schema string) *internal.Command) (bool, error) {
+	schema, err := dialect.Schema(ctx, executor)
+	if err != nil {
+		return false, err
+	}
+	// QueryRowContext 的适配器可能不检查 ctx，见 internal.Legacy
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	cmd := build(schema)
+	var count int
+	if err := executor.QueryRowContext(ctx, cmd.SQL(), cmd.Args()...).Scan(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func query(ctx context.Context, executor internal.Executor, cmd *internal.Command, dest interface{}) error {
+	rows, err := executor.QueryContext(ctx, cmd.SQL(), cmd.Args()...)
+	if err != nil {
+		return err
+	}
+	return internal.ScanAll(rows, dest, internal.UnknownIgnore)
+}
@@ -0,0 +1,74 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-01-28 10:05
+// version: 1.0.0
+// desc   : 
+
+package dialect
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LiteralTimeLayout 时间字面量的格式
+const LiteralTimeLayout = "2006-01-02 15:04:05.999999"
+
+// Literal 将参数渲染为 sql 字面量，字符串中的单引号会被转义
+func (*Creator) Literal(value interface{}) string {
+	switch v := Normalize(value).(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case []byte:
+		return "X'" + hex.EncodeToString(v) + "'"
+	case time.Time:
+		return QuoteString(v.Format(LiteralTimeLayout))
+	case string:
+		return QuoteString(v)
+	default:
+		return QuoteString(fmt.Sprintf("%v", v))
+	}
+}
+
+// Normalize 将参数转换为 driver.Value 的基础类型，解引用指针并调用 driver.Valuer
+func Normalize(value interface{}) interface{} {
+	result, err := driver.DefaultParameterConverter.ConvertValue(value)
+	if err != nil {
+		return value
+	}
+	return result
+}
+
+// QuoteString 使用单引号包裹字符串，内部单引号双写转义
+func QuoteString(value string) string {
+	return "'" + strings.Replace(value, "'", "''", -1) + "'"
+}
@@ -0,0 +1,55 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-02-10 10:20
+// version: 1.0.0
+// desc   : 
+
+package mariadb
+
+import (
+	"fmt"
+	"github.com/yhyzgn/glue/dialect"
+	"github.com/yhyzgn/glue/dialect/mysql"
+	"github.com/yhyzgn/glue/internal"
+)
+
+// MariaDB 在 mysql 的基础上支持 INSERT ... RETURNING（10.5 及以上）和序列（10.3 及以上）
+type MariaDB struct {
+	*mysql.MySQL
+}
+
+func Dialect() *MariaDB {
+	dl := &MariaDB{&mysql.MySQL{Creator: dialect.New(new(mariadb))}}
+	dl.Bind(dl)
+	return dl
+}
+
+func (m *MariaDB) Returning(column string, index int) (output, suffix string) {
+	return "", "RETURNING " + m.Quote(column)
+}
+
+func (m *MariaDB) CreateSequence(name string, start, increment int64) *internal.Command {
+	return internal.NewCommand(fmt.Sprintf("CREATE SEQUENCE %s START WITH %d INCREMENT BY %d", m.Quote(name), start, increment))
+}
+
+func (m *MariaDB) DropSequence(name string) *internal.Command {
+	return internal.NewCommand("DROP SEQUENCE " + m.Quote(name))
+}
+
+func (m *MariaDB) NextValue(name string) string {
+	return fmt.Sprintf("NEXTVAL(%s)", m.Quote(name))
+}
@@ -0,0 +1,51 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-02-28 10:10
+// version: 1.0.0
+// desc   : 
+
+package mariadb
+
+import (
+	"github.com/yhyzgn/glue/internal"
+	"github.com/yhyzgn/glue/primary"
+	"testing"
+)
+
+func TestInsert(t *testing.T) {
+	dl := Dialect()
+	cmd := dl.Insert(&internal.ExecValue{Table: "user", Columns: []string{"name"}, Values: []interface{}{"glue"}, Primary: "id", Type: internal.ExecInsert})
+	if cmd.SQL() != "INSERT INTO `user` (`name`) VALUES (?) RETURNING `id`" || cmd.GeneratedKey() != "id" {
+		t.Errorf("unexpected insert: %s", cmd.SQL())
+	}
+
+	// 序列作为主键时写入取下一个值的表达式
+	value := &internal.ExecValue{Table: "user", Columns: []string{"id", "name"}, Values: []interface{}{(&primary.Sequence{Name: "seq_user"}).Primary(), "glue"}, Primary: "id"}
+	if cmd = dl.Insert(value); cmd.SQL() != "INSERT INTO `user` (`id`, `name`) VALUES (NEXTVAL(`seq_user`), ?) RETURNING `id`" || len(cmd.Args()) != 1 {
+		t.Errorf("unexpected sequence insert: %s %v", cmd.SQL(), cmd.Args())
+	}
+}
+
+func TestSequence(t *testing.T) {
+	dl := Dialect()
+	if sql := dl.CreateSequence("seq_user", 1, 1).SQL(); sql != "CREATE SEQUENCE `seq_user` START WITH 1 INCREMENT BY 1" {
+		t.Errorf("unexpected create sequence: %s", sql)
+	}
+	if sql := dl.DropSequence("seq_user").SQL(); sql != "DROP SEQUENCE `seq_user`" {
+		t.Errorf("unexpected drop sequence: %s", sql)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-01-30 14:05
+// version: 1.0.0
+// desc   : 
+
+package mssql
+
+import (
+	"github.com/yhyzgn/glue/internal"
+)
+
+// mssql 的表结构从 sys.* 目录视图读取，限定在当前用户的默认架构中
+
+func (*MSSQL) TablesQuery() *internal.Command {
+	return internal.NewCommand("SELECT t.name AS name FROM sys.tables t").
+		Line("WHERE t.schema_id = SCHEMA_ID()").
+		Line("ORDER BY t.name")
+}
+
+// ColumnsQuery sys.columns 中 nvarchar 的 max_length 为字节数，需要折半
+func (m *MSSQL) ColumnsQuery(table string) *internal.Command {
+	return internal.NewCommand("SELECT c.name AS name, TYPE_NAME(c.user_type_id) + CASE").
+		TabsLine("WHEN TYPE_NAME(c.user_type_id) IN ('varchar', 'char', 'varbinary', 'binary') THEN '(' + CASE WHEN c.max_length = -1 THEN 'MAX' ELSE CAST(c.max_length AS VARCHAR(10)) END + ')'", 2).
+		TabsLine("WHEN TYPE_NAME(c.user_type_id) IN ('nvarchar', 'nchar') THEN '(' + CASE WHEN c.max_length = -1 THEN 'MAX' ELSE CAST(c.max_length / 2 AS VARCHAR(10)) END + ')'", 2).
+		TabsLine("WHEN TYPE_NAME(c.user_type_id) IN ('decimal', 'numeric') THEN '(' + CAST(c.precision AS VARCHAR(10)) + ',' + CAST(c.scale AS VARCHAR(10)) + ')'", 2).
+		TabsLine("ELSE '' END AS type,", 2).
+		TabLine("c.is_nullable AS nullable, dc.definition AS dflt, CAST(ep.value AS NVARCHAR(4000)) AS remarks, c.column_id AS ordinal, c.is_identity AS is_identity").
+		Line("FROM sys.columns c").
+		Line("JOIN sys.tables t ON t.object_id = c.object_id").
+		Line("LEFT JOIN sys.default_constraints dc ON dc.object_id = c.default_object_id").
+		Line("LEFT JOIN sys.extended_properties ep ON ep.major_id = c.object_id AND ep.minor_id = c.column_id AND ep.name = 'MS_Description'").
+		Line("WHERE t.name = " + m.Placeholder(1) + " AND t.schema_id = SCHEMA_ID()").
+		Line("ORDER BY c.column_id").
+		Arguments(table)
+}
+
+func (m *MSSQL) PrimaryKeysQuery(table string) *internal.Command {
+	return internal.NewCommand("SELECT c.name AS name").
+		Line("FROM sys.indexes i").
+		Line("JOIN sys.tables t ON t.object_id = i.object_id").
+		Line("JOIN sys.index_columns ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id").
+		Line("JOIN sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id").
+		Line("WHERE i.is_primary_key = 1 AND t.name = " + m.Placeholder(1) + " AND t.schema_id = SCHEMA_ID()").
+		Line("ORDER BY ic.key_ordinal").
+		Arguments(table)
+}
+
+func (m *MSSQL) IndexesQuery(table string) *internal.Command {
+	return internal.NewCommand("SELECT i.name AS name, c.name AS column_name, i.is_unique AS is_unique").
+		Line("FROM sys.indexes i").
+		Line("JOIN sys.tables t ON t.object_id = i.object_id").
+		Line("JOIN sys.index_columns ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id").
+		Line("JOIN sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id").
+		Line("WHERE i.is_primary_key = 0 AND i.type > 0 AND t.name = " + m.Placeholder(1) + " AND t.schema_id = SCHEMA_ID()").
+		Line("ORDER BY i.name, ic.key_ordinal").
+		Arguments(table)
+}
+
+func (m *MSSQL) ForeignKeysQuery(table string) *internal.Command {
+	return internal.NewCommand("SELECT fk.name AS name, c.name AS column_name, rt.name AS ref_table, rc.name AS ref_column").
+		Line("FROM sys.foreign_keys fk").
+		Line("JOIN sys.foreign_key_columns fkc ON fkc.constraint_object_id = fk.object_id").
+		Line("JOIN sys.tables t ON t.object_id = fk.parent_object_id").
+		Line("JOIN sys.columns c ON c.object_id = fkc.parent_object_id AND c.column_id = fkc.parent_column_id").
+		Line("JOIN sys.tables rt ON rt.object_id = fkc.referenced_object_id").
+		Line("JOIN sys.columns rc ON rc.object_id = fkc.referenced_object_id AND rc.column_id = fkc.referenced_column_id").
+		Line("WHERE t.name = " + m.Placeholder(1) + " AND t.schema_id = SCHEMA_ID()").
+		Line("ORDER BY fk.name, fkc.constraint_column_id").
+		Arguments(table)
+}
@@ -22,9 +22,19 @@ package mssql
 
 import (
 	"fmt"
-	_ "github.com/denisenkom/go-mssqldb"
+	mssqldb "github.com/denisenkom/go-mssqldb"
+	"github.com/yhyzgn/glue/dialect"
+	"github.com/yhyzgn/glue/internal"
 )
 
+func init() {
+	dialect.Register("mssql", &mssqldb.Driver{}, func() internal.Dialect {
+		return Dialect()
+	})
+}
+
+var quoter = dialect.Quoter{Open: "[", Close: "]"}
+
 type mssql struct {
 }
 
@@ -37,13 +47,14 @@ func (*mssql) Driver() string {
 }
 
 func (*mssql) Quote(key string) string {
-	return fmt.Sprintf("[%s]", key)
+	return quoter.Quote(key)
 }
 
 func (*mssql) Placeholder(index int) string {
-	return "?"
+	return fmt.Sprintf("@p%d", index)
 }
 
+// Database INFORMATION_SCHEMA 中的 table_schema 是 schema（如 dbo）而不是数据库名
 func (*mssql) Database() string {
-	return "SELECT DATABASE()"
+	return "SELECT SCHEMA_NAME()"
 }
@@ -0,0 +1,76 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-02-20 12:00
+// version: 1.0.0
+// desc   : 
+
+package mssql
+
+import (
+	"errors"
+	mssqldb "github.com/denisenkom/go-mssqldb"
+	"github.com/yhyzgn/glue/dialect"
+	"github.com/yhyzgn/glue/internal"
+	"regexp"
+	"strings"
+)
+
+var (
+	quotedConstraint = regexp.MustCompile(`constraint '([^']+)'|constraint "([^"]+)"|unique index '([^']+)'`)
+	duplicateObject  = regexp.MustCompile(`in object '([^']+)'`)
+	nullColumn       = regexp.MustCompile(`into column '([^']+)', table '([^']+)'`)
+	invalidObject    = regexp.MustCompile(`Invalid object name '([^']+)'`)
+)
+
+// ParseError 按错误号解析，547 同时用于外键和检查约束，按错误信息区分，
+// 1205 死锁，1222 锁请求超时，3960 快照隔离下的更新冲突
+func (*MSSQL) ParseError(err error) *internal.Error {
+	var me mssqldb.Error
+	if !errors.As(err, &me) {
+		return nil
+	}
+	result := &internal.Error{Err: err}
+	switch me.Number {
+	case 1205:
+		result.Kind = internal.KindDeadlock
+	case 1222:
+		result.Kind = internal.KindLockTimeout
+	case 3960:
+		result.Kind = internal.KindSerialization
+	case 2627, 2601:
+		result.Kind = internal.KindUniqueViolation
+		result.Constraint = dialect.Submatch(quotedConstraint, me.Message)
+		result.Table = dialect.Unqualify(dialect.Submatch(duplicateObject, me.Message))
+	case 547:
+		result.Kind = internal.KindForeignKeyViolation
+		if strings.Contains(me.Message, "CHECK constraint") {
+			result.Kind = internal.KindCheckViolation
+		}
+		result.Constraint = dialect.Submatch(quotedConstraint, me.Message)
+	case 515:
+		result.Kind = internal.KindNotNullViolation
+		if match := nullColumn.FindStringSubmatch(me.Message); match != nil {
+			result.Column, result.Table = match[1], dialect.Unqualify(match[2])
+		}
+	case 208:
+		result.Kind = internal.KindTableNotFound
+		result.Table = dialect.Unqualify(dialect.Submatch(invalidObject, me.Message))
+	default:
+		return nil
+	}
+	return result
+}
@@ -21,7 +21,13 @@
 package mssql
 
 import (
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"github.com/yhyzgn/glue/dialect"
+	"github.com/yhyzgn/glue/internal"
+	"reflect"
+	"strings"
 )
 
 type MSSQL struct {
@@ -29,6 +35,145 @@ type MSSQL struct {
 }
 
 func Dialect() *MSSQL {
-	dialect.Current = &MSSQL{dialect.New(new(mssql))}
-	return dialect.Current.(*MSSQL)
+	dl := &MSSQL{dialect.New(new(mssql))}
+	dl.Bind(dl)
+	return dl
+}
+
+func (*MSSQL) SQLType(field *reflect.StructField) string {
+	return types.SQLType(field)
+}
+
+// Limit mssql 使用 OFFSET ... FETCH，且必须有 ORDER BY，没有时按任意顺序排序
+func (m *MSSQL) Limit(cmd *internal.Command, limit, offset int) *internal.Command {
+	result := cmd.Clone()
+	if limit <= 0 && offset <= 0 {
+		return result
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	if !dialect.HasOrderBy(cmd.SQL()) {
+		result.Line("ORDER BY (SELECT NULL)")
+	}
+	result.Line("OFFSET " + m.Placeholder(len(result.Args())+1) + " ROWS").Arguments(offset)
+	if limit > 0 {
+		result.Line("FETCH NEXT " + m.Placeholder(len(result.Args())+1) + " ROWS ONLY").Arguments(limit)
+	}
+	return result
+}
+
+// Seek mssql 的子查询只有带 TOP 或 OFFSET 时才能使用 ORDER BY，排序由 keys 决定，cmd 中不需要 ORDER BY
+func (m *MSSQL) Seek(cmd *internal.Command, keys []*internal.Key, cursor *internal.Cursor, size int) (*internal.Command, error) {
+	upper := strings.ToUpper(cmd.SQL())
+	if dialect.HasOrderBy(upper) && !strings.Contains(upper, "OFFSET") && !strings.Contains(upper, "TOP") {
+		return nil, errors.New("glue: mssql seek query can not contain ORDER BY without TOP or OFFSET, the order is defined by keys")
+	}
+	return m.Creator.Seek(cmd, keys, cursor, size)
+}
+
+func (m *MSSQL) Returning(column string, index int) (output, suffix string) {
+	return "OUTPUT INSERTED." + m.Quote(column), ""
+}
+
+// Literal mssql 没有布尔字面量，字符串使用 N 前缀的字面量以保留 unicode
+func (m *MSSQL) Literal(value interface{}) string {
+	switch v := dialect.Normalize(value).(type) {
+	case bool:
+		if v {
+			return "1"
+		}
+		return "0"
+	case []byte:
+		return "0x" + hex.EncodeToString(v)
+	case string:
+		return "N" + dialect.QuoteString(v)
+	}
+	return m.Creator.Literal(value)
+}
+
+func (*MSSQL) Identity(table *internal.Definition, field *internal.Field) (string, bool) {
+	return field.SQLType + " IDENTITY(1,1) NOT NULL", false
+}
+
+// Comment mssql 的列注释保存在扩展属性 MS_Description 中
+func (m *MSSQL) Comment(table string, field *internal.Field) (string, *internal.Command) {
+	return "", m.description(internal.NewCommand("DECLARE @schema SYSNAME = SCHEMA_NAME();").Line("EXEC sp_addextendedproperty"), table, field.Column, field.Comment)
+}
+
+// AddColumn mssql 的 ADD 不带 COLUMN 关键字
+func (m *MSSQL) AddColumn(table, column, tpy, comment string, notNull bool, defValue interface{}) []*internal.Command {
+	cmd := internal.NewCommand(fmt.Sprintf("ALTER TABLE %s ADD %s %s", m.Quote(table), m.Quote(column), dialect.ColumnClause(tpy, notNull, defValue)))
+	return m.WithComment(cmd, table, column, comment)
+}
+
+// ModifyColumn mssql 的默认值是单独的约束，ALTER COLUMN 不能修改，保持不变；注释已存在时更新
+func (m *MSSQL) ModifyColumn(table, column, rename, tpy, comment string, notNull bool, defValue interface{}) []*internal.Command {
+	commands := make([]*internal.Command, 0, 3)
+	if rename != "" && rename != column {
+		commands = append(commands, internal.NewCommand(fmt.Sprintf("EXEC sp_rename %s, %s, 'COLUMN'", m.Literal(table+"."+column), m.Literal(rename))))
+		column = rename
+	}
+	nullable := "NULL"
+	if notNull {
+		nullable = "NOT NULL"
+	}
+	commands = append(commands, internal.NewCommand(fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s %s %s", m.Quote(table), m.Quote(column), tpy, nullable)))
+	if comment == "" {
+		return commands
+	}
+	cmd := internal.NewCommand("DECLARE @schema SYSNAME = SCHEMA_NAME();").
+		Line("IF EXISTS (SELECT 1 FROM sys.extended_properties WHERE name = N'MS_Description'").
+		TabLine("AND major_id = OBJECT_ID(QUOTENAME(@schema) + '.' + QUOTENAME(" + m.Literal(table) + "))").
+		TabLine("AND minor_id = COLUMNPROPERTY(major_id, " + m.Literal(column) + ", 'ColumnId'))").
+		Line("EXEC sp_updateextendedproperty")
+	cmd = m.description(cmd, table, column, comment).Line("ELSE").Line("EXEC sp_addextendedproperty")
+	return append(commands, m.description(cmd, table, column, comment))
+}
+
+// description 追加 sp_addextendedproperty 等存储过程设置 MS_Description 的参数
+func (m *MSSQL) description(cmd *internal.Command, table, column, comment string) *internal.Command {
+	return cmd.TabLine("@name = N'MS_Description', @value = " + m.Literal(comment) + ",").
+		TabLine("@level0type = N'SCHEMA', @level0name = @schema,").
+		TabLine("@level1type = N'TABLE', @level1name = " + m.Literal(table) + ",").
+		TabLine("@level2type = N'COLUMN', @level2name = " + m.Literal(column))
+}
+
+// HasIndex mssql 的 INFORMATION_SCHEMA 中没有索引，从 sys.indexes 读取
+func (m *MSSQL) HasIndex(schema, table, name string) *internal.Command {
+	return internal.NewCommand("SELECT").
+		TabLine("COUNT(*)").
+		Line("FROM").
+		TabLine("sys.indexes i").
+		TabLine("JOIN sys.tables t ON t.object_id = i.object_id").
+		Line("WHERE").
+		TabLine("t.schema_id = SCHEMA_ID("+m.Placeholder(1)+")").
+		TabLine("AND t.name = "+m.Placeholder(2)).
+		TabLine("AND i.name = "+m.Placeholder(3)).
+		Arguments(schema, table, name)
+}
+
+func (*MSSQL) Savepoint(name string) *internal.Command {
+	return internal.NewCommand("SAVE TRANSACTION " + name)
+}
+
+func (*MSSQL) RollbackTo(name string) *internal.Command {
+	return internal.NewCommand("ROLLBACK TRANSACTION " + name)
+}
+
+// ReleaseSavepoint mssql 的保存点随事务结束释放
+func (*MSSQL) ReleaseSavepoint(name string) *internal.Command {
+	return nil
+}
+
+func (m *MSSQL) CreateSequence(name string, start, increment int64) *internal.Command {
+	return internal.NewCommand(fmt.Sprintf("CREATE SEQUENCE %s AS BIGINT START WITH %d INCREMENT BY %d", m.Quote(name), start, increment))
+}
+
+func (m *MSSQL) DropSequence(name string) *internal.Command {
+	return internal.NewCommand("DROP SEQUENCE " + m.Quote(name))
+}
+
+func (m *MSSQL) NextValue(name string) string {
+	return "NEXT VALUE FOR " + m.Quote(name)
 }
@@ -0,0 +1,57 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-01-19 14:48
+// version: 1.0.0
+// desc   : 
+
+package mssql
+
+import (
+	"fmt"
+	"github.com/yhyzgn/glue/dialect"
+)
+
+// mssql 的 TINYINT 是无符号的，int8 只能用 SMALLINT 保存
+var types = &dialect.Types{
+	Bool:    "BIT",
+	Int8:    "SMALLINT",
+	Int16:   "SMALLINT",
+	Int32:   "INT",
+	Int64:   "BIGINT",
+	Uint8:   "TINYINT",
+	Uint16:  "INT",
+	Uint32:  "BIGINT",
+	Uint64:  "NUMERIC(20,0)",
+	Float32: "REAL",
+	Float64: "FLOAT",
+	Time:    "DATETIME2",
+	String: func(size int) string {
+		if size <= 0 || size > 4000 {
+			return "NVARCHAR(MAX)"
+		}
+		return fmt.Sprintf("NVARCHAR(%d)", size)
+	},
+	Bytes: func(size int) string {
+		if size <= 0 || size > 8000 {
+			return "VARBINARY(MAX)"
+		}
+		return fmt.Sprintf("VARBINARY(%d)", size)
+	},
+	Decimal: func(precision, scale int) string {
+		return fmt.Sprintf("DECIMAL(%d,%d)", precision, scale)
+	},
+}
@@ -21,10 +21,19 @@
 package mysql
 
 import (
-	"fmt"
-	_ "github.com/go-sql-driver/mysql"
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/yhyzgn/glue/dialect"
+	"github.com/yhyzgn/glue/internal"
 )
 
+func init() {
+	dialect.Register("mysql", &mysqldriver.MySQLDriver{}, func() internal.Dialect {
+		return Dialect()
+	})
+}
+
+var quoter = dialect.Quoter{Open: "`", Close: "`"}
+
 type mysql struct {
 }
 
@@ -37,7 +46,7 @@ func (*mysql) Driver() string {
 }
 
 func (*mysql) Quote(key string) string {
-	return fmt.Sprintf("`%s`", key)
+	return quoter.Quote(key)
 }
 
 func (*mysql) Placeholder(index int) string {
@@ -0,0 +1,73 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-02-20 11:40
+// version: 1.0.0
+// desc   : 
+
+package mysql
+
+import (
+	"errors"
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/yhyzgn/glue/dialect"
+	"github.com/yhyzgn/glue/internal"
+	"regexp"
+)
+
+var (
+	duplicateKey    = regexp.MustCompile("for key '([^']+)'")
+	foreignKey      = regexp.MustCompile("`([^`]+)`, CONSTRAINT `([^`]+)` FOREIGN KEY \\(`([^`]+)`")
+	nullColumn      = regexp.MustCompile("(?:Column|Field) '([^']+)'")
+	checkConstraint = regexp.MustCompile("[Cc]heck constraint '([^']+)'|CONSTRAINT `([^`]+)` failed")
+	missingTable    = regexp.MustCompile("Table '([^']+)' doesn't exist")
+)
+
+// ParseError 按错误号解析，mariadb 和 tidb 使用相同的错误号，约束名等从错误信息中提取
+func (*MySQL) ParseError(err error) *internal.Error {
+	var me *mysqldriver.MySQLError
+	if !errors.As(err, &me) {
+		return nil
+	}
+	result := &internal.Error{Err: err}
+	switch me.Number {
+	case 1213:
+		result.Kind = internal.KindDeadlock
+	case 1205:
+		result.Kind = internal.KindLockTimeout
+	case 1062:
+		// mysql 8 的索引名带有表名前缀
+		result.Kind = internal.KindUniqueViolation
+		result.Constraint = dialect.Unqualify(dialect.Submatch(duplicateKey, me.Message))
+	case 1451, 1452:
+		result.Kind = internal.KindForeignKeyViolation
+		if match := foreignKey.FindStringSubmatch(me.Message); match != nil {
+			result.Table, result.Constraint, result.Column = match[1], match[2], match[3]
+		}
+	case 1048, 1364:
+		result.Kind = internal.KindNotNullViolation
+		result.Column = dialect.Submatch(nullColumn, me.Message)
+	case 3819, 4025:
+		result.Kind = internal.KindCheckViolation
+		result.Constraint = dialect.Submatch(checkConstraint, me.Message)
+	case 1146:
+		result.Kind = internal.KindTableNotFound
+		result.Table = dialect.Unqualify(dialect.Submatch(missingTable, me.Message))
+	default:
+		return nil
+	}
+	return result
+}
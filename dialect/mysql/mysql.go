@@ -21,7 +21,12 @@
 package mysql
 
 import (
+	"fmt"
 	"github.com/yhyzgn/glue/dialect"
+	"github.com/yhyzgn/glue/internal"
+	"reflect"
+	"regexp"
+	"strings"
 )
 
 type MySQL struct {
@@ -29,6 +34,85 @@ type MySQL struct {
 }
 
 func Dialect() *MySQL {
-	dialect.Current = &MySQL{dialect.New(new(mysql))}
-	return dialect.Current.(*MySQL)
+	dl := &MySQL{dialect.New(new(mysql))}
+	dl.Bind(dl)
+	return dl
+}
+
+func (*MySQL) SQLType(field *reflect.StructField) string {
+	return types.SQLType(field)
+}
+
+// DefaultValue mysql 不支持 DEFAULT VALUES
+func (*MySQL) DefaultValue() string {
+	return "VALUES ()"
+}
+
+// Limit mysql 的 OFFSET 必须跟在 LIMIT 之后，不限条数时使用文档建议的最大值
+func (m *MySQL) Limit(cmd *internal.Command, limit, offset int) *internal.Command {
+	if limit > 0 || offset <= 0 {
+		return m.Creator.Limit(cmd, limit, offset)
+	}
+	return cmd.Clone().
+		Line("LIMIT 18446744073709551615").
+		Line("OFFSET " + m.Placeholder(len(cmd.Args())+1)).
+		Arguments(offset)
+}
+
+func (*MySQL) RowComparison() bool {
+	return true
+}
+
+// AddIndex mysql 支持 FULLTEXT 和 SPATIAL 索引
+func (m *MySQL) AddIndex(table, name string, indexes []*internal.Index) *internal.Command {
+	if len(indexes) == 0 {
+		return nil
+	}
+	kind := ""
+	switch indexes[0].Type {
+	case internal.IndexFullText:
+		kind = "FULLTEXT"
+	case internal.IndexSpatial:
+		kind = "SPATIAL"
+	default:
+		return m.Creator.AddIndex(table, name, indexes)
+	}
+	columns := make([]string, len(indexes))
+	for i, index := range indexes {
+		columns[i] = m.Quote(index.Column)
+	}
+	return internal.NewCommand(fmt.Sprintf("CREATE %s INDEX %s ON %s (%s)", kind, m.Quote(name), m.Quote(table), strings.Join(columns, ", ")))
+}
+
+// Literal mysql 默认将反斜杠视为转义符，需要一并转义
+func (m *MySQL) Literal(value interface{}) string {
+	if v, ok := dialect.Normalize(value).(string); ok {
+		return dialect.QuoteString(strings.Replace(v, `\`, `\\`, -1))
+	}
+	return m.Creator.Literal(value)
+}
+
+func (*MySQL) Identity(table *internal.Definition, field *internal.Field) (string, bool) {
+	return field.SQLType + " NOT NULL AUTO_INCREMENT", false
+}
+
+func (m *MySQL) Comment(table string, field *internal.Field) (string, *internal.Command) {
+	return "COMMENT " + m.Literal(field.Comment), nil
+}
+
+// displayWidth 整数类型的显示宽度，8.0.19 之前 column_type 中总是带有，如 int(11)
+var displayWidth = regexp.MustCompile(`^(tinyint|smallint|mediumint|int|integer|bigint)\(\d+\)`)
+
+// NormalizeType 去掉整数类型的显示宽度，tinyint(1) 表示布尔值，保留
+func (*MySQL) NormalizeType(tpy string) string {
+	tpy = strings.ToLower(strings.TrimSpace(tpy))
+	switch tpy {
+	case "bool", "boolean", "tinyint(1)":
+		return "tinyint(1)"
+	}
+	tpy = displayWidth.ReplaceAllString(tpy, "$1")
+	if strings.HasPrefix(tpy, "integer") {
+		tpy = "int" + strings.TrimPrefix(tpy, "integer")
+	}
+	return tpy
 }
@@ -0,0 +1,66 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-01-19 14:10
+// version: 1.0.0
+// desc   : 
+
+package mysql
+
+import (
+	"fmt"
+	"github.com/yhyzgn/glue/dialect"
+)
+
+var types = &dialect.Types{
+	Bool:    "TINYINT(1)",
+	Int8:    "TINYINT",
+	Int16:   "SMALLINT",
+	Int32:   "INT",
+	Int64:   "BIGINT",
+	Uint8:   "TINYINT UNSIGNED",
+	Uint16:  "SMALLINT UNSIGNED",
+	Uint32:  "INT UNSIGNED",
+	Uint64:  "BIGINT UNSIGNED",
+	Float32: "FLOAT",
+	Float64: "DOUBLE",
+	Time:    "DATETIME",
+	String: func(size int) string {
+		switch {
+		case size <= 0 || size > 16777215:
+			return "LONGTEXT"
+		case size > 65535:
+			return "MEDIUMTEXT"
+		case size > 16383:
+			return "TEXT"
+		}
+		return fmt.Sprintf("VARCHAR(%d)", size)
+	},
+	Bytes: func(size int) string {
+		switch {
+		case size <= 0 || size > 16777215:
+			return "LONGBLOB"
+		case size > 65535:
+			return "MEDIUMBLOB"
+		case size > 65532:
+			return "BLOB"
+		}
+		return fmt.Sprintf("VARBINARY(%d)", size)
+	},
+	Decimal: func(precision, scale int) string {
+		return fmt.Sprintf("DECIMAL(%d,%d)", precision, scale)
+	},
+}
@@ -0,0 +1,84 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-01-30 15:20
+// version: 1.0.0
+// desc   : 
+
+package oracle
+
+import (
+	"github.com/yhyzgn/glue/internal"
+)
+
+// oracle 的表结构从 ALL_* 数据字典视图读取，限定在当前会话的 schema 中，
+// 数据字典中的名称为大写，表名参数按引用时的规则折叠为大写
+const currentSchema = "SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA')"
+
+func (*Oracle) TablesQuery() *internal.Command {
+	return internal.NewCommand("SELECT table_name AS name FROM all_tables").
+		Line("WHERE owner = " + currentSchema).
+		Line("ORDER BY table_name")
+}
+
+// ColumnsQuery 11g 及以下没有标识列，自增由序列和触发器实现，无法从列信息判断
+func (o *Oracle) ColumnsQuery(table string) *internal.Command {
+	identity := "CASE WHEN c.identity_column = 'YES' THEN 1 ELSE 0 END"
+	if o.legacy {
+		identity = "0"
+	}
+	return internal.NewCommand("SELECT c.column_name AS name, c.data_type || CASE").
+		TabsLine("WHEN c.data_type IN ('VARCHAR2', 'NVARCHAR2', 'CHAR', 'NCHAR') THEN '(' || c.char_length || ')'", 2).
+		TabsLine("WHEN c.data_type = 'RAW' THEN '(' || c.data_length || ')'", 2).
+		TabsLine("WHEN c.data_type = 'NUMBER' AND c.data_precision IS NOT NULL THEN '(' || c.data_precision || CASE WHEN c.data_scale > 0 THEN ',' || c.data_scale END || ')'", 2).
+		TabsLine("END AS type,", 2).
+		TabLine("CASE WHEN c.nullable = 'Y' THEN 1 ELSE 0 END AS nullable, c.data_default AS dflt, cc.comments AS remarks, c.column_id AS ordinal,").
+		TabLine(identity + " AS is_identity").
+		Line("FROM all_tab_columns c").
+		Line("LEFT JOIN all_col_comments cc ON cc.owner = c.owner AND cc.table_name = c.table_name AND cc.column_name = c.column_name").
+		Line("WHERE c.owner = " + currentSchema + " AND c.table_name = " + o.Placeholder(1)).
+		Line("ORDER BY c.column_id").
+		Arguments(quoter.Folding.Fold(table))
+}
+
+func (o *Oracle) PrimaryKeysQuery(table string) *internal.Command {
+	return internal.NewCommand("SELECT cc.column_name AS name").
+		Line("FROM all_constraints con").
+		Line("JOIN all_cons_columns cc ON cc.owner = con.owner AND cc.constraint_name = con.constraint_name").
+		Line("WHERE con.constraint_type = 'P' AND con.owner = " + currentSchema + " AND con.table_name = " + o.Placeholder(1)).
+		Line("ORDER BY cc.position").
+		Arguments(quoter.Folding.Fold(table))
+}
+
+func (o *Oracle) IndexesQuery(table string) *internal.Command {
+	return internal.NewCommand("SELECT i.index_name AS name, ic.column_name AS column_name, CASE WHEN i.uniqueness = 'UNIQUE' THEN 1 ELSE 0 END AS is_unique").
+		Line("FROM all_indexes i").
+		Line("JOIN all_ind_columns ic ON ic.index_owner = i.owner AND ic.index_name = i.index_name").
+		Line("WHERE i.table_owner = " + currentSchema + " AND i.table_name = " + o.Placeholder(1)).
+		TabLine("AND NOT EXISTS (SELECT 1 FROM all_constraints con WHERE con.owner = i.owner AND con.index_name = i.index_name AND con.constraint_type = 'P')").
+		Line("ORDER BY i.index_name, ic.column_position").
+		Arguments(quoter.Folding.Fold(table))
+}
+
+func (o *Oracle) ForeignKeysQuery(table string) *internal.Command {
+	return internal.NewCommand("SELECT con.constraint_name AS name, cc.column_name AS column_name, rc.table_name AS ref_table, rc.column_name AS ref_column").
+		Line("FROM all_constraints con").
+		Line("JOIN all_cons_columns cc ON cc.owner = con.owner AND cc.constraint_name = con.constraint_name").
+		Line("JOIN all_cons_columns rc ON rc.owner = con.r_owner AND rc.constraint_name = con.r_constraint_name AND rc.position = cc.position").
+		Line("WHERE con.constraint_type = 'R' AND con.owner = " + currentSchema + " AND con.table_name = " + o.Placeholder(1)).
+		Line("ORDER BY con.constraint_name, cc.position").
+		Arguments(quoter.Folding.Fold(table))
+}
@@ -22,9 +22,23 @@ package oracle
 
 import (
 	"fmt"
-	_ "github.com/mattn/go-oci8"
+	"github.com/mattn/go-oci8"
+	"github.com/yhyzgn/glue/dialect"
+	"github.com/yhyzgn/glue/internal"
 )
 
+func init() {
+	dialect.Register("oracle", oci8.OCI8Driver, func() internal.Dialect {
+		return Dialect()
+	})
+	dialect.Register("oracle-legacy", nil, func() internal.Dialect {
+		return LegacyDialect()
+	})
+}
+
+// oracle 将未加引号的标识符折叠为大写，引用时同样转为大写，数据字典中的名称也是大写
+var quoter = dialect.Quoter{Open: `"`, Close: `"`, Folding: dialect.FoldUpper}
+
 type oracle struct {
 }
 
@@ -37,13 +51,17 @@ func (*oracle) Driver() string {
 }
 
 func (*oracle) Quote(key string) string {
-	return fmt.Sprintf("`%s`", key)
+	return quoter.Quote(key)
 }
 
 func (*oracle) Placeholder(index int) string {
-	return "?"
+	return fmt.Sprintf(":%d", index)
 }
 
 func (*oracle) Database() string {
-	return "SELECT DATABASE()"
+	return "SELECT SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA') FROM DUAL"
+}
+
+func (*oracle) Fold(name string) string {
+	return quoter.Folding.Fold(name)
 }
@@ -0,0 +1,84 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-02-20 12:20
+// version: 1.0.0
+// desc   : 
+
+package oracle
+
+import (
+	"github.com/yhyzgn/glue/dialect"
+	"github.com/yhyzgn/glue/internal"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// oci8 的错误只有文本，错误码从 ORA-xxxxx 前缀中解析
+	oraCode         = regexp.MustCompile(`ORA-(\d{5})`)
+	namedConstraint = regexp.MustCompile(`constraint \(([^)]+)\)`)
+	nullColumn      = regexp.MustCompile(`into \(([^)]+)\)`)
+)
+
+func errorCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	match := oraCode.FindStringSubmatch(err.Error())
+	if match == nil {
+		return 0
+	}
+	code, _ := strconv.Atoi(match[1])
+	return code
+}
+
+// ParseError ORA-00060 死锁，ORA-08177 可串行化冲突，ORA-00054 和 ORA-30006 资源忙或等待超时，
+// 约束名带有 schema 前缀，解析时去掉
+func (*Oracle) ParseError(err error) *internal.Error {
+	result := &internal.Error{Err: err}
+	switch errorCode(err) {
+	case 60:
+		result.Kind = internal.KindDeadlock
+	case 8177:
+		result.Kind = internal.KindSerialization
+	case 54, 30006:
+		result.Kind = internal.KindLockTimeout
+	case 1:
+		result.Kind = internal.KindUniqueViolation
+		result.Constraint = dialect.Unqualify(dialect.Submatch(namedConstraint, err.Error()))
+	case 2291, 2292:
+		result.Kind = internal.KindForeignKeyViolation
+		result.Constraint = dialect.Unqualify(dialect.Submatch(namedConstraint, err.Error()))
+	case 2290:
+		result.Kind = internal.KindCheckViolation
+		result.Constraint = dialect.Unqualify(dialect.Submatch(namedConstraint, err.Error()))
+	case 1400:
+		// 形如 ("APP"."T"."C")
+		result.Kind = internal.KindNotNullViolation
+		parts := strings.Split(dialect.Submatch(nullColumn, err.Error()), ".")
+		if len(parts) >= 2 {
+			result.Table = dialect.Unqualify(parts[len(parts)-2])
+			result.Column = dialect.Unqualify(parts[len(parts)-1])
+		}
+	case 942:
+		result.Kind = internal.KindTableNotFound
+	default:
+		return nil
+	}
+	return result
+}
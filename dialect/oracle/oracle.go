@@ -21,14 +21,271 @@
 package oracle
 
 import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
 	"github.com/yhyzgn/glue/dialect"
+	"github.com/yhyzgn/glue/internal"
+	"reflect"
+	"strings"
+	"time"
 )
 
 type Oracle struct {
 	*dialect.Creator
+	legacy bool
 }
 
+// Dialect 适用于 oracle 12c 及以上版本
 func Dialect() *Oracle {
-	dialect.Current = &Oracle{dialect.New(new(oracle))}
-	return dialect.Current.(*Oracle)
+	dl := &Oracle{Creator: dialect.New(new(oracle))}
+	dl.Bind(dl)
+	return dl
+}
+
+// LegacyDialect 适用于 oracle 11g 及以下版本，分页使用 ROWNUM 实现
+func LegacyDialect() *Oracle {
+	dl := Dialect()
+	dl.legacy = true
+	return dl
+}
+
+func (*Oracle) SQLType(field *reflect.StructField) string {
+	return types.SQLType(field)
+}
+
+// Limit 12c 及以上使用 OFFSET ... FETCH，旧版本使用 ROWNUM 嵌套查询，结果中会多出 RN__ 列
+func (o *Oracle) Limit(cmd *internal.Command, limit, offset int) *internal.Command {
+	if limit <= 0 && offset <= 0 {
+		return cmd.Clone()
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	if o.legacy {
+		result := internal.NewCommand("SELECT * FROM (").
+			TabLine("SELECT T.*, ROWNUM AS RN__ FROM (").
+			Line(cmd.SQL()).
+			TabLine(") T").
+			Arguments(cmd.Args()...)
+		if limit > 0 {
+			result.Space("WHERE ROWNUM <= " + o.Placeholder(len(result.Args())+1)).Arguments(offset + limit)
+		}
+		return result.Line(") WHERE RN__ > " + o.Placeholder(len(result.Args())+1)).Arguments(offset)
+	}
+
+	result := cmd.Clone()
+	if offset > 0 {
+		result.Line("OFFSET " + o.Placeholder(len(result.Args())+1) + " ROWS").Arguments(offset)
+	}
+	if limit > 0 {
+		result.Line("FETCH NEXT " + o.Placeholder(len(result.Args())+1) + " ROWS ONLY").Arguments(limit)
+	}
+	return result
+}
+
+func (o *Oracle) Returning(column string, index int) (output, suffix string) {
+	return "", fmt.Sprintf("RETURNING %s INTO %s", o.Quote(column), o.Placeholder(index))
+}
+
+// Insert oracle 不支持 DEFAULT VALUES，没有列可写时对主键写入 DEFAULT 以触发标识列或序列触发器
+func (o *Oracle) Insert(value *internal.ExecValue) *internal.Command {
+	if value != nil && len(value.Columns) == 0 && value.Primary != "" {
+		copied := *value
+		copied.Columns = []string{value.Primary}
+		copied.Values = []interface{}{keyword("DEFAULT")}
+		value = &copied
+	}
+	return o.Creator.Insert(value)
+}
+
+// keyword 原样写入插入语句的关键字
+type keyword string
+
+func (k keyword) Expression(internal.Dialect) (string, error) {
+	return string(k), nil
+}
+
+// InsertExecutor oracle 通过 RETURNING ... INTO 的输出参数取回主键
+func (o *Oracle) InsertExecutor(ctx context.Context, executor internal.Executor, command *internal.Command) (sql.Result, error) {
+	if command == nil || command.GeneratedKey() == "" {
+		return o.Creator.InsertExecutor(ctx, executor, command)
+	}
+	dest := command.Destination()
+	if dest == nil {
+		dest = new(int64)
+	}
+	args := append(append(make([]interface{}, 0), command.Args()...), sql.Out{Dest: dest})
+	result, err := executor.ExecContext(ctx, command.SQL(), args...)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	return internal.KeyResult(dest, rows), nil
+}
+
+// Literal oracle 没有布尔字面量，时间使用 TIMESTAMP 字面量以避免依赖会话的日期格式
+func (o *Oracle) Literal(value interface{}) string {
+	switch v := dialect.Normalize(value).(type) {
+	case bool:
+		if v {
+			return "1"
+		}
+		return "0"
+	case []byte:
+		return "HEXTORAW('" + hex.EncodeToString(v) + "')"
+	case time.Time:
+		return "TIMESTAMP " + dialect.QuoteString(v.Format(dialect.LiteralTimeLayout))
+	}
+	return o.Creator.Literal(value)
+}
+
+// Identity 12c 及以上使用标识列，旧版本由 CreateTable 生成的序列和触发器填充
+func (o *Oracle) Identity(table *internal.Definition, field *internal.Field) (string, bool) {
+	if o.legacy {
+		return field.SQLType + " NOT NULL", false
+	}
+	return field.SQLType + " GENERATED BY DEFAULT AS IDENTITY", false
+}
+
+func (o *Oracle) CreateTable(definition *internal.Definition) []*internal.Command {
+	commands := o.Creator.CreateTable(definition)
+	identity := dialect.IdentityOf(o, definition)
+	if !o.legacy || identity == nil {
+		return commands
+	}
+	sequence := o.Quote("seq_" + definition.TableName)
+	return append(commands,
+		internal.NewCommand("CREATE SEQUENCE "+sequence),
+		internal.NewCommand(fmt.Sprintf("CREATE OR REPLACE TRIGGER %s", o.Quote("trg_"+definition.TableName))).
+			Line(fmt.Sprintf("BEFORE INSERT ON %s FOR EACH ROW", o.Quote(definition.TableName))).
+			Line(fmt.Sprintf("WHEN (NEW.%s IS NULL)", o.Quote(identity.Column))).
+			Line(fmt.Sprintf("BEGIN SELECT %s.NEXTVAL INTO :NEW.%s FROM DUAL; END;", sequence, o.Quote(identity.Column))),
+	)
+}
+
+// AddColumn oracle 的 ADD 不带 COLUMN 关键字，列定义写在括号中
+func (o *Oracle) AddColumn(table, column, tpy, comment string, notNull bool, defValue interface{}) []*internal.Command {
+	cmd := internal.NewCommand(fmt.Sprintf("ALTER TABLE %s ADD (%s %s)", o.Quote(table), o.Quote(column), dialect.ColumnClause(tpy, notNull, defValue)))
+	return o.WithComment(cmd, table, column, comment)
+}
+
+// ModifyColumn 可空性与原来相同时 MODIFY 会报错 ORA-01442/ORA-01451，
+// 因此单独修改可空性并忽略这两个错误，类型和默认值在同一条语句中修改
+func (o *Oracle) ModifyColumn(table, column, rename, tpy, comment string, notNull bool, defValue interface{}) []*internal.Command {
+	commands := make([]*internal.Command, 0, 4)
+	if rename != "" && rename != column {
+		commands = append(commands, internal.NewCommand(fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s", o.Quote(table), o.Quote(column), o.Quote(rename))))
+		column = rename
+	}
+	clause := tpy
+	if defValue != nil {
+		clause += fmt.Sprintf(" DEFAULT %v", defValue)
+	}
+	cmd := internal.NewCommand(fmt.Sprintf("ALTER TABLE %s MODIFY (%s %s)", o.Quote(table), o.Quote(column), clause))
+	commands = append(commands, o.WithComment(cmd, table, column, comment)...)
+
+	nullable := "NULL"
+	if notNull {
+		nullable = "NOT NULL"
+	}
+	alter := fmt.Sprintf("ALTER TABLE %s MODIFY (%s %s)", o.Quote(table), o.Quote(column), nullable)
+	return append(commands, internal.NewCommand("BEGIN").
+		TabLine("EXECUTE IMMEDIATE "+o.Literal(alter)+";").
+		Line("EXCEPTION").
+		TabLine("WHEN OTHERS THEN").
+		TabsLine("IF SQLCODE NOT IN (-1442, -1451) THEN", 2).
+		TabsLine("RAISE;", 3).
+		TabsLine("END IF;", 2).
+		Line("END;"))
+}
+
+// NormalizeType 数据字典中的 VARCHAR2 长度不带 CHAR/BYTE 语义
+func (*Oracle) NormalizeType(tpy string) string {
+	tpy = strings.ToLower(strings.TrimSpace(tpy))
+	return strings.NewReplacer(" char)", ")", " byte)", ")").Replace(tpy)
+}
+
+// oracle 没有 INFORMATION_SCHEMA，存在性检查从 ALL_* 数据字典视图读取，名称按大写比较
+
+func (o *Oracle) HasTable(schema, name string) *internal.Command {
+	return internal.NewCommand("SELECT").
+		TabLine("COUNT(*)").
+		Line("FROM").
+		TabLine("all_tables").
+		Line("WHERE").
+		TabLine("owner = "+o.Placeholder(1)).
+		TabLine("AND table_name = "+o.Placeholder(2)).
+		Arguments(schema, quoter.Folding.Fold(name))
+}
+
+func (o *Oracle) Columns(schema, table string) *internal.Command {
+	return internal.NewCommand("SELECT").
+		TabLine("*").
+		Line("FROM").
+		TabLine("all_tab_columns").
+		Line("WHERE").
+		TabLine("owner = "+o.Placeholder(1)).
+		TabLine("AND table_name = "+o.Placeholder(2)).
+		Line("ORDER BY").
+		TabLine("column_id ASC").
+		Arguments(schema, quoter.Folding.Fold(table))
+}
+
+func (o *Oracle) HasColumn(schema, table, column string) *internal.Command {
+	return internal.NewCommand("SELECT").
+		TabLine("COUNT(*)").
+		Line("FROM").
+		TabLine("all_tab_columns").
+		Line("WHERE").
+		TabLine("owner = "+o.Placeholder(1)).
+		TabLine("AND table_name = "+o.Placeholder(2)).
+		TabLine("AND column_name = "+o.Placeholder(3)).
+		Arguments(schema, quoter.Folding.Fold(table), quoter.Folding.Fold(column))
+}
+
+func (o *Oracle) HasIndex(schema, table, name string) *internal.Command {
+	return internal.NewCommand("SELECT").
+		TabLine("COUNT(*)").
+		Line("FROM").
+		TabLine("all_indexes").
+		Line("WHERE").
+		TabLine("table_owner = "+o.Placeholder(1)).
+		TabLine("AND table_name = "+o.Placeholder(2)).
+		TabLine("AND index_name = "+o.Placeholder(3)).
+		Arguments(schema, quoter.Folding.Fold(table), quoter.Folding.Fold(name))
+}
+
+func (o *Oracle) HasForeignKey(schema, table, name string) *internal.Command {
+	return internal.NewCommand("SELECT").
+		TabLine("COUNT(*)").
+		Line("FROM").
+		TabLine("all_constraints").
+		Line("WHERE").
+		TabLine("owner = "+o.Placeholder(1)).
+		TabLine("AND table_name = "+o.Placeholder(2)).
+		TabLine("AND constraint_name = "+o.Placeholder(3)).
+		TabLine("AND constraint_type = 'R'").
+		Arguments(schema, quoter.Folding.Fold(table), quoter.Folding.Fold(name))
+}
+
+// ReleaseSavepoint oracle 的保存点随事务结束释放
+func (*Oracle) ReleaseSavepoint(name string) *internal.Command {
+	return nil
+}
+
+func (o *Oracle) CreateSequence(name string, start, increment int64) *internal.Command {
+	return internal.NewCommand(fmt.Sprintf("CREATE SEQUENCE %s START WITH %d INCREMENT BY %d", o.Quote(name), start, increment))
+}
+
+func (o *Oracle) DropSequence(name string) *internal.Command {
+	return internal.NewCommand("DROP SEQUENCE " + o.Quote(name))
+}
+
+func (o *Oracle) NextValue(name string) string {
+	return o.Quote(name) + ".NEXTVAL"
 }
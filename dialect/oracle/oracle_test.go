@@ -0,0 +1,55 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-02-27 11:00
+// version: 1.0.0
+// desc   : 
+
+package oracle
+
+import (
+	"github.com/yhyzgn/glue/internal"
+	"strings"
+	"testing"
+)
+
+func TestInsert(t *testing.T) {
+	cmd := Dialect().Insert(&internal.ExecValue{Table: "account", Primary: "id", Type: internal.ExecInsert})
+	if cmd.SQL() != `INSERT INTO "ACCOUNT" ("ID") VALUES (DEFAULT) RETURNING "ID" INTO :1` || len(cmd.Args()) != 0 {
+		t.Errorf("unexpected insert: %s %v", cmd.SQL(), cmd.Args())
+	}
+	cmd = Dialect().Insert(&internal.ExecValue{Table: "account", Columns: []string{"name"}, Values: []interface{}{"glue"}, Primary: "id"})
+	if cmd.SQL() != `INSERT INTO "ACCOUNT" ("NAME") VALUES (:1) RETURNING "ID" INTO :2` {
+		t.Errorf("unexpected insert: %s", cmd.SQL())
+	}
+}
+
+func TestAlterColumn(t *testing.T) {
+	commands := Dialect().AddColumn("user", "age", "NUMBER(10)", "it's the age", true, 0)
+	if len(commands) != 2 || commands[0].SQL() != `ALTER TABLE "USER" ADD ("AGE" NUMBER(10) DEFAULT 0 NOT NULL)` || commands[1].SQL() != `COMMENT ON COLUMN "USER"."AGE" IS 'it''s the age'` {
+		t.Errorf("unexpected add column: %v", commands)
+	}
+	commands = Dialect().ModifyColumn("user", "age", "age", "NUMBER(19)", "", true, nil)
+	if len(commands) != 2 || commands[0].SQL() != `ALTER TABLE "USER" MODIFY ("AGE" NUMBER(19))` || !strings.Contains(commands[1].SQL(), `EXECUTE IMMEDIATE 'ALTER TABLE "USER" MODIFY ("AGE" NOT NULL)';`) {
+		t.Errorf("unexpected modify column: %v", commands)
+	}
+}
+
+func TestNormalizeType(t *testing.T) {
+	if tpy := Dialect().NormalizeType("VARCHAR2(255 CHAR)"); tpy != Dialect().NormalizeType("VARCHAR2(255)") {
+		t.Errorf("unexpected normalized type %s", tpy)
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-01-19 14:40
+// version: 1.0.0
+// desc   : 
+
+package oracle
+
+import (
+	"fmt"
+	"github.com/yhyzgn/glue/dialect"
+)
+
+var types = &dialect.Types{
+	Bool:    "NUMBER(1)",
+	Int8:    "NUMBER(3)",
+	Int16:   "NUMBER(5)",
+	Int32:   "NUMBER(10)",
+	Int64:   "NUMBER(19)",
+	Uint8:   "NUMBER(3)",
+	Uint16:  "NUMBER(5)",
+	Uint32:  "NUMBER(10)",
+	Uint64:  "NUMBER(20)",
+	Float32: "BINARY_FLOAT",
+	Float64: "BINARY_DOUBLE",
+	Time:    "TIMESTAMP",
+	String: func(size int) string {
+		if size <= 0 || size > 4000 {
+			return "CLOB"
+		}
+		return fmt.Sprintf("VARCHAR2(%d CHAR)", size)
+	},
+	Bytes: func(size int) string {
+		if size <= 0 || size > 2000 {
+			return "BLOB"
+		}
+		return fmt.Sprintf("RAW(%d)", size)
+	},
+	Decimal: func(precision, scale int) string {
+		return fmt.Sprintf("NUMBER(%d,%d)", precision, scale)
+	},
+}
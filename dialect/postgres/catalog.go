@@ -0,0 +1,86 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-01-30 11:40
+// version: 1.0.0
+// desc   : 
+
+package postgres
+
+import (
+	"github.com/yhyzgn/glue/internal"
+)
+
+// postgres 的表结构从 pg_catalog 读取，限定在 current_schema() 中，cockroach 兼容这些查询，
+// 表名参数按引用时的规则折叠为小写
+
+func (*Postgres) TablesQuery() *internal.Command {
+	return internal.NewCommand("SELECT c.relname AS name").
+		Line("FROM pg_catalog.pg_class c").
+		Line("JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace").
+		Line("WHERE c.relkind IN ('r', 'p') AND n.nspname = current_schema()").
+		Line("ORDER BY c.relname")
+}
+
+func (p *Postgres) ColumnsQuery(table string) *internal.Command {
+	return internal.NewCommand("SELECT a.attname AS name, pg_catalog.format_type(a.atttypid, a.atttypmod) AS type, NOT a.attnotnull AS nullable,").
+		TabLine("pg_catalog.pg_get_expr(d.adbin, d.adrelid) AS dflt, pg_catalog.col_description(c.oid, a.attnum) AS remarks, a.attnum AS ordinal,").
+		TabLine("a.attidentity <> '' OR COALESCE(pg_catalog.pg_get_expr(d.adbin, d.adrelid), '') LIKE 'nextval(%' OR pg_catalog.pg_get_expr(d.adbin, d.adrelid) = 'unique_rowid()' AS is_identity").
+		Line("FROM pg_catalog.pg_attribute a").
+		Line("JOIN pg_catalog.pg_class c ON c.oid = a.attrelid").
+		Line("JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace").
+		Line("LEFT JOIN pg_catalog.pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum").
+		Line("WHERE c.relname = " + p.Placeholder(1) + " AND n.nspname = current_schema() AND a.attnum > 0 AND NOT a.attisdropped").
+		Line("ORDER BY a.attnum").
+		Arguments(quoter.Folding.Fold(table))
+}
+
+func (p *Postgres) PrimaryKeysQuery(table string) *internal.Command {
+	return internal.NewCommand("SELECT a.attname AS name").
+		Line("FROM pg_catalog.pg_index i").
+		Line("JOIN pg_catalog.pg_class c ON c.oid = i.indrelid").
+		Line("JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace").
+		Line("JOIN pg_catalog.pg_attribute a ON a.attrelid = c.oid AND a.attnum = ANY(i.indkey)").
+		Line("WHERE i.indisprimary AND c.relname = " + p.Placeholder(1) + " AND n.nspname = current_schema()").
+		Line("ORDER BY array_position(i.indkey::int2[], a.attnum)").
+		Arguments(quoter.Folding.Fold(table))
+}
+
+func (p *Postgres) IndexesQuery(table string) *internal.Command {
+	return internal.NewCommand("SELECT ic.relname AS name, a.attname AS column_name, i.indisunique AS is_unique").
+		Line("FROM pg_catalog.pg_index i").
+		Line("JOIN pg_catalog.pg_class c ON c.oid = i.indrelid").
+		Line("JOIN pg_catalog.pg_class ic ON ic.oid = i.indexrelid").
+		Line("JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace").
+		Line("JOIN pg_catalog.pg_attribute a ON a.attrelid = c.oid AND a.attnum = ANY(i.indkey)").
+		Line("WHERE NOT i.indisprimary AND c.relname = " + p.Placeholder(1) + " AND n.nspname = current_schema()").
+		Line("ORDER BY ic.relname, array_position(i.indkey::int2[], a.attnum)").
+		Arguments(quoter.Folding.Fold(table))
+}
+
+func (p *Postgres) ForeignKeysQuery(table string) *internal.Command {
+	return internal.NewCommand("SELECT con.conname AS name, a.attname AS column_name, rc.relname AS ref_table, ra.attname AS ref_column").
+		Line("FROM pg_catalog.pg_constraint con").
+		Line("JOIN pg_catalog.pg_class c ON c.oid = con.conrelid").
+		Line("JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace").
+		Line("JOIN pg_catalog.pg_class rc ON rc.oid = con.confrelid").
+		Line("CROSS JOIN LATERAL unnest(con.conkey, con.confkey) WITH ORDINALITY AS k(attnum, refnum, ord)").
+		Line("JOIN pg_catalog.pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum").
+		Line("JOIN pg_catalog.pg_attribute ra ON ra.attrelid = con.confrelid AND ra.attnum = k.refnum").
+		Line("WHERE con.contype = 'f' AND c.relname = " + p.Placeholder(1) + " AND n.nspname = current_schema()").
+		Line("ORDER BY con.conname, k.ord").
+		Arguments(quoter.Folding.Fold(table))
+}
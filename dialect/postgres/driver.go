@@ -22,9 +22,20 @@ package postgres
 
 import (
 	"fmt"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
+	"github.com/yhyzgn/glue/dialect"
+	"github.com/yhyzgn/glue/internal"
 )
 
+func init() {
+	dialect.Register("postgres", &pq.Driver{}, func() internal.Dialect {
+		return Dialect()
+	})
+}
+
+// postgres 将未加引号的标识符折叠为小写，引用时同样转为小写
+var quoter = dialect.Quoter{Open: `"`, Close: `"`, Folding: dialect.FoldLower}
+
 type postgres struct {
 }
 
@@ -37,7 +48,7 @@ func (*postgres) Driver() string {
 }
 
 func (*postgres) Quote(key string) string {
-	return fmt.Sprintf("`%s`", key)
+	return quoter.Quote(key)
 }
 
 func (*postgres) Placeholder(index int) string {
@@ -45,5 +56,9 @@ func (*postgres) Placeholder(index int) string {
 }
 
 func (*postgres) Database() string {
-	return "SELECT DATABASE()"
+	return "SELECT current_schema()"
+}
+
+func (*postgres) Fold(name string) string {
+	return quoter.Folding.Fold(name)
 }
@@ -0,0 +1,63 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-02-20 11:50
+// version: 1.0.0
+// desc   : 
+
+package postgres
+
+import (
+	"errors"
+	"github.com/lib/pq"
+	"github.com/yhyzgn/glue/dialect"
+	"github.com/yhyzgn/glue/internal"
+	"regexp"
+)
+
+var missingRelation = regexp.MustCompile(`relation "([^"]+)" does not exist`)
+
+// ParseError 按 SQLSTATE 解析，约束、表、列名取自 pq.Error 的对应字段，
+// 40001 可串行化冲突（cockroach 的事务重试错误也是此码），40P01 死锁，55P03 无法获得锁
+func (*Postgres) ParseError(err error) *internal.Error {
+	var pe *pq.Error
+	if !errors.As(err, &pe) {
+		return nil
+	}
+	result := &internal.Error{Constraint: pe.Constraint, Table: pe.Table, Column: pe.Column, Err: err}
+	switch pe.Code {
+	case "40001":
+		result.Kind = internal.KindSerialization
+	case "40P01":
+		result.Kind = internal.KindDeadlock
+	case "55P03":
+		result.Kind = internal.KindLockTimeout
+	case "23505":
+		result.Kind = internal.KindUniqueViolation
+	case "23503":
+		result.Kind = internal.KindForeignKeyViolation
+	case "23502":
+		result.Kind = internal.KindNotNullViolation
+	case "23514":
+		result.Kind = internal.KindCheckViolation
+	case "42P01":
+		result.Kind = internal.KindTableNotFound
+		result.Table = dialect.Unqualify(dialect.Submatch(missingRelation, pe.Message))
+	default:
+		return nil
+	}
+	return result
+}
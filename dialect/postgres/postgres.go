@@ -21,7 +21,12 @@
 package postgres
 
 import (
+	"encoding/hex"
+	"fmt"
 	"github.com/yhyzgn/glue/dialect"
+	"github.com/yhyzgn/glue/internal"
+	"reflect"
+	"strings"
 )
 
 type Postgres struct {
@@ -29,6 +34,109 @@ type Postgres struct {
 }
 
 func Dialect() *Postgres {
-	dialect.Current = &Postgres{dialect.New(new(postgres))}
-	return dialect.Current.(*Postgres)
+	dl := &Postgres{dialect.New(new(postgres))}
+	dl.Bind(dl)
+	return dl
+}
+
+func (*Postgres) SQLType(field *reflect.StructField) string {
+	return types.SQLType(field)
+}
+
+func (*Postgres) RowComparison() bool {
+	return true
+}
+
+func (p *Postgres) Returning(column string, index int) (output, suffix string) {
+	return "", "RETURNING " + p.Quote(column)
+}
+
+func (p *Postgres) Literal(value interface{}) string {
+	if v, ok := dialect.Normalize(value).([]byte); ok {
+		return `'\x` + hex.EncodeToString(v) + "'::bytea"
+	}
+	return p.Creator.Literal(value)
+}
+
+func (*Postgres) Identity(table *internal.Definition, field *internal.Field) (string, bool) {
+	return field.SQLType + " GENERATED BY DEFAULT AS IDENTITY", false
+}
+
+// ModifyColumn postgres 分别修改类型、可空性和默认值，改名和注释为单独的语句
+func (p *Postgres) ModifyColumn(table, column, rename, tpy, comment string, notNull bool, defValue interface{}) []*internal.Command {
+	commands := make([]*internal.Command, 0, 3)
+	if rename != "" && rename != column {
+		commands = append(commands, internal.NewCommand(fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s", p.Quote(table), p.Quote(column), p.Quote(rename))))
+		column = rename
+	}
+	alters := []string{fmt.Sprintf("ALTER COLUMN %s TYPE %s", p.Quote(column), tpy)}
+	if notNull {
+		alters = append(alters, fmt.Sprintf("ALTER COLUMN %s SET NOT NULL", p.Quote(column)))
+	} else {
+		alters = append(alters, fmt.Sprintf("ALTER COLUMN %s DROP NOT NULL", p.Quote(column)))
+	}
+	if defValue != nil {
+		alters = append(alters, fmt.Sprintf("ALTER COLUMN %s SET DEFAULT %v", p.Quote(column), defValue))
+	} else {
+		alters = append(alters, fmt.Sprintf("ALTER COLUMN %s DROP DEFAULT", p.Quote(column)))
+	}
+	cmd := internal.NewCommand(fmt.Sprintf("ALTER TABLE %s %s", p.Quote(table), strings.Join(alters, ", ")))
+	return append(commands, p.WithComment(cmd, table, column, comment)...)
+}
+
+// typeNames format_type 返回的类型名与建表时使用的类型名的对应关系
+var typeNames = map[string]string{
+	"character varying":           "varchar",
+	"character":                   "char",
+	"int":                         "integer",
+	"int2":                        "smallint",
+	"int4":                        "integer",
+	"int8":                        "bigint",
+	"bool":                        "boolean",
+	"float4":                      "real",
+	"float8":                      "double precision",
+	"decimal":                     "numeric",
+	"timestamptz":                 "timestamp with time zone",
+	"timestamp without time zone": "timestamp",
+}
+
+// NormalizeType 统一类型别名，numeric(p) 与 numeric(p,0) 相同
+func (*Postgres) NormalizeType(tpy string) string {
+	base, args := strings.ToLower(strings.TrimSpace(tpy)), ""
+	if idx := strings.Index(base, "("); idx >= 0 {
+		base, args = strings.TrimSpace(base[:idx]), strings.Replace(base[idx:], " ", "", -1)
+	}
+	if name, ok := typeNames[base]; ok {
+		base = name
+	}
+	if base == "numeric" && args != "" && !strings.Contains(args, ",") {
+		args = strings.TrimSuffix(args, ")") + ",0)"
+	}
+	return base + args
+}
+
+// HasIndex postgres 的 information_schema 中没有索引，从 pg_indexes 读取
+func (p *Postgres) HasIndex(schema, table, name string) *internal.Command {
+	return internal.NewCommand("SELECT").
+		TabLine("COUNT(*)").
+		Line("FROM").
+		TabLine("pg_catalog.pg_indexes").
+		Line("WHERE").
+		TabLine("schemaname = "+p.Placeholder(1)).
+		TabLine("AND tablename = "+p.Placeholder(2)).
+		TabLine("AND indexname = "+p.Placeholder(3)).
+		Arguments(schema, quoter.Folding.Fold(table), quoter.Folding.Fold(name))
+}
+
+func (p *Postgres) CreateSequence(name string, start, increment int64) *internal.Command {
+	return internal.NewCommand(fmt.Sprintf("CREATE SEQUENCE %s START WITH %d INCREMENT BY %d", p.Quote(name), start, increment))
+}
+
+func (p *Postgres) DropSequence(name string) *internal.Command {
+	return internal.NewCommand("DROP SEQUENCE " + p.Quote(name))
+}
+
+// NextValue nextval 的参数是 regclass 文本，需要带上引用后的名称
+func (p *Postgres) NextValue(name string) string {
+	return fmt.Sprintf("nextval(%s)", p.Literal(p.Quote(name)))
 }
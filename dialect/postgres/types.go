@@ -0,0 +1,54 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-01-19 14:25
+// version: 1.0.0
+// desc   : 
+
+package postgres
+
+import (
+	"fmt"
+	"github.com/yhyzgn/glue/dialect"
+)
+
+// postgres 没有无符号整数，使用更宽的类型保存
+var types = &dialect.Types{
+	Bool:    "BOOLEAN",
+	Int8:    "SMALLINT",
+	Int16:   "SMALLINT",
+	Int32:   "INTEGER",
+	Int64:   "BIGINT",
+	Uint8:   "SMALLINT",
+	Uint16:  "INTEGER",
+	Uint32:  "BIGINT",
+	Uint64:  "NUMERIC(20)",
+	Float32: "REAL",
+	Float64: "DOUBLE PRECISION",
+	Time:    "TIMESTAMP WITH TIME ZONE",
+	String: func(size int) string {
+		if size <= 0 || size > 10485760 {
+			return "TEXT"
+		}
+		return fmt.Sprintf("VARCHAR(%d)", size)
+	},
+	Bytes: func(size int) string {
+		return "BYTEA"
+	},
+	Decimal: func(precision, scale int) string {
+		return fmt.Sprintf("NUMERIC(%d,%d)", precision, scale)
+	},
+}
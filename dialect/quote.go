@@ -0,0 +1,78 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-02-15 14:20
+// version: 1.0.0
+// desc   : 
+
+package dialect
+
+import "strings"
+
+// Folding 数据库对未加引号标识符的大小写折叠方式
+type Folding int
+
+const (
+	// FoldNone 保持原样，如 mysql、sqlite、mssql
+	FoldNone Folding = iota
+	// FoldLower 折叠为小写，如 postgres
+	FoldLower
+	// FoldUpper 折叠为大写，如 oracle
+	FoldUpper
+)
+
+// Fold 按折叠方式转换标识符，引用后的名称与手写 SQL 中未加引号的名称指向同一对象
+func (f Folding) Fold(name string) string {
+	switch f {
+	case FoldLower:
+		return strings.ToLower(name)
+	case FoldUpper:
+		return strings.ToUpper(name)
+	}
+	return name
+}
+
+// Quoter 方言的标识符引用规则，名称中出现的 Close 字符按 Escape 转义，Escape 为空时重复 Close，
+// 带 . 的名称视为 schema.table 或 table.column，逐段引用，* 不引用
+type Quoter struct {
+	Open    string
+	Close   string
+	Escape  string
+	Folding Folding
+}
+
+func (q Quoter) Quote(key string) string {
+	if key == "" {
+		return key
+	}
+	escape := q.Escape
+	if escape == "" {
+		escape = q.Close + q.Close
+	}
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		if part == "*" {
+			continue
+		}
+		parts[i] = q.Open + strings.Replace(q.Folding.Fold(part), q.Close, escape, -1) + q.Close
+	}
+	return strings.Join(parts, ".")
+}
+
+// Folder 会折叠标识符的驱动实现此接口，目录查询中的名称参数按同样规则折叠后再与数据字典比较
+type Folder interface {
+	Fold(name string) string
+}
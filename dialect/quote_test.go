@@ -0,0 +1,58 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-02-15 15:05
+// version: 1.0.0
+// desc   : 
+
+package dialect
+
+import (
+	"github.com/yhyzgn/glue/internal"
+	"testing"
+)
+
+func TestQuoter_Quote(t *testing.T) {
+	cases := []struct {
+		quoter   Quoter
+		key      string
+		expected string
+	}{
+		{Quoter{Open: "`", Close: "`"}, "user", "`user`"},
+		{Quoter{Open: "`", Close: "`"}, "we`ird", "`we``ird`"},
+		{Quoter{Open: "`", Close: "`", Escape: "\\`"}, "we`ird", "`we\\`ird`"},
+		{Quoter{Open: "[", Close: "]"}, "a]b", "[a]]b]"},
+		{Quoter{Open: `"`, Close: `"`}, `say "hi"`, `"say ""hi"""`},
+		{Quoter{Open: `"`, Close: `"`, Folding: FoldLower}, "App.User", `"app"."user"`},
+		{Quoter{Open: `"`, Close: `"`, Folding: FoldUpper}, "app.user", `"APP"."USER"`},
+		{Quoter{Open: `"`, Close: `"`}, "t.*", `"t".*`},
+		{Quoter{Open: `"`, Close: `"`}, "", ""},
+	}
+	for _, cs := range cases {
+		if quoted := cs.quoter.Quote(cs.key); quoted != cs.expected {
+			t.Errorf("unexpected quoted %s: %s, expected %s", cs.key, quoted, cs.expected)
+		}
+	}
+}
+
+func TestCreator_AddForeignKey(t *testing.T) {
+	dft := New(new(testDriver))
+	key := &internal.ForeignKey{Name: "fk_member_group", Column: "group_id, tenant_id", Table: "groups", Reference: "id,tenant_id"}
+	expected := `ALTER TABLE "member" ADD CONSTRAINT "fk_member_group" FOREIGN KEY ("group_id", "tenant_id") REFERENCES "groups" ("id", "tenant_id")`
+	if sql := dft.AddForeignKey("member", key).SQL(); sql != expected {
+		t.Errorf("unexpected foreign key: %s", sql)
+	}
+}
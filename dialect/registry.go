@@ -0,0 +1,112 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-02-05 09:30
+// version: 1.0.0
+// desc   : 
+
+package dialect
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"github.com/yhyzgn/glue/internal"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// Factory 创建方言实例，每次调用返回新的实例
+type Factory func() internal.Dialect
+
+var registry = struct {
+	sync.RWMutex
+	factories map[string]Factory
+	// drivers 数据库驱动名称、驱动类型到方言名称的映射，同一驱动以先注册的方言为准
+	drivers map[string]string
+	types   map[reflect.Type]string
+}{
+	factories: make(map[string]Factory),
+	drivers:   make(map[string]string),
+	types:     make(map[reflect.Type]string),
+}
+
+// Register 注册方言，drv 为该方言使用的 database/sql 驱动实例，用于从 *sql.DB 查找方言，可以为 nil
+//
+// 通常在方言包的 init 中调用，名称重复时 panic
+func Register(name string, drv driver.Driver, factory Factory) {
+	if factory == nil {
+		panic("glue: register dialect with nil factory")
+	}
+	registry.Lock()
+	defer registry.Unlock()
+	if _, ok := registry.factories[name]; ok {
+		panic(fmt.Sprintf("glue: dialect '%s' is registered twice", name))
+	}
+	registry.factories[name] = factory
+	if driverName := factory().Driver(); driverName != "" {
+		if _, ok := registry.drivers[driverName]; !ok {
+			registry.drivers[driverName] = name
+		}
+	}
+	if drv != nil {
+		if _, ok := registry.types[reflect.TypeOf(drv)]; !ok {
+			registry.types[reflect.TypeOf(drv)] = name
+		}
+	}
+}
+
+// Lookup 按方言名称或驱动名称查找方言，如 "postgres"、"sqlite3"，返回新的实例
+func Lookup(name string) (internal.Dialect, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	factory, ok := registry.factories[name]
+	if !ok {
+		if name, ok = registry.drivers[name]; ok {
+			factory = registry.factories[name]
+		}
+	}
+	if !ok {
+		return nil, false
+	}
+	return factory(), true
+}
+
+// FromDB 按 db 使用的驱动类型查找方言
+func FromDB(db *sql.DB) (internal.Dialect, error) {
+	tp := reflect.TypeOf(db.Driver())
+	registry.RLock()
+	name, ok := registry.types[tp]
+	registry.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("glue: no dialect registered for driver %v", tp)
+	}
+	dl, _ := Lookup(name)
+	return dl, nil
+}
+
+// Dialects 已注册的方言名称
+func Dialects() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+	names := make([]string, 0, len(registry.factories))
+	for name := range registry.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,67 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-01-30 11:00
+// version: 1.0.0
+// desc   : 
+
+package sqlite
+
+import (
+	"github.com/yhyzgn/glue/internal"
+)
+
+// sqlite 没有 information_schema，表结构通过 PRAGMA 表值函数读取
+
+func (*SQLite) TablesQuery() *internal.Command {
+	return internal.NewCommand("SELECT name FROM sqlite_master").
+		Line("WHERE type = 'table' AND name NOT LIKE 'sqlite_%'").
+		Line("ORDER BY name")
+}
+
+// ColumnsQuery 只有单列的 INTEGER 主键是 rowid 的别名，由数据库自增
+func (*SQLite) ColumnsQuery(table string) *internal.Command {
+	return internal.NewCommand(`SELECT name, type, NOT "notnull" AS nullable, dflt_value AS dflt, '' AS remarks, cid + 1 AS ordinal,`).
+		TabLine(`pk = 1 AND UPPER(type) = 'INTEGER' AND (SELECT COUNT(*) FROM pragma_table_info(?) WHERE pk > 0) = 1 AS is_identity`).
+		Line("FROM pragma_table_info(?)").
+		Line("ORDER BY cid").
+		Arguments(table, table)
+}
+
+func (*SQLite) PrimaryKeysQuery(table string) *internal.Command {
+	return internal.NewCommand("SELECT name FROM pragma_table_info(?)").
+		Line("WHERE pk > 0").
+		Line("ORDER BY pk").
+		Arguments(table)
+}
+
+func (*SQLite) IndexesQuery(table string) *internal.Command {
+	return internal.NewCommand(`SELECT il.name AS name, ii.name AS column_name, il."unique" AS is_unique`).
+		Line("FROM pragma_index_list(?) il").
+		Line("JOIN pragma_index_info(il.name) ii").
+		Line("WHERE il.origin <> 'pk'").
+		Line("ORDER BY il.name, ii.seqno").
+		Arguments(table)
+}
+
+// ForeignKeysQuery sqlite 不保存外键名，按 fk_表名_首列 命名，与解析模型时的默认外键名一致
+func (*SQLite) ForeignKeysQuery(table string) *internal.Command {
+	return internal.NewCommand(`SELECT (SELECT 'fk_' || ? || '_' || f0."from" FROM pragma_foreign_key_list(?) f0 WHERE f0.id = f.id AND f0.seq = 0) AS name,`).
+		TabLine(`f."from" AS column_name, f."table" AS ref_table, f."to" AS ref_column`).
+		Line("FROM pragma_foreign_key_list(?) f").
+		Line("ORDER BY f.id, f.seq").
+		Arguments(table, table, table)
+}
@@ -21,9 +21,11 @@
 package sqlite
 
 import (
-	"fmt"
+	"github.com/yhyzgn/glue/dialect"
 )
 
+var quoter = dialect.Quoter{Open: `"`, Close: `"`}
+
 type sqlite struct {
 }
 
@@ -36,13 +38,14 @@ func (*sqlite) Driver() string {
 }
 
 func (*sqlite) Quote(key string) string {
-	return fmt.Sprintf("`%s`", key)
+	return quoter.Quote(key)
 }
 
 func (*sqlite) Placeholder(index int) string {
 	return "?"
 }
 
+// Database sqlite 的主数据库固定名为 main，附加的数据库使用 ATTACH 时指定的名称
 func (*sqlite) Database() string {
-	return "SELECT DATABASE()"
+	return "SELECT 'main'"
 }
@@ -0,0 +1,77 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-02-20 12:10
+// version: 1.0.0
+// desc   : 
+
+package sqlite
+
+import (
+	"errors"
+	"github.com/mattn/go-sqlite3"
+	"github.com/yhyzgn/glue/internal"
+	"strings"
+)
+
+// ParseError 按扩展错误码解析，表名和列名取自 "UNIQUE constraint failed: t.c" 形式的错误信息，
+// sqlite 只有库级和表级锁，SQLITE_BUSY 和 SQLITE_LOCKED 都按等待锁超时处理
+func (*SQLite) ParseError(err error) *internal.Error {
+	var se sqlite3.Error
+	if !errors.As(err, &se) {
+		return nil
+	}
+	result := &internal.Error{Err: err}
+	msg := se.Error()
+	switch {
+	case se.Code == sqlite3.ErrBusy || se.Code == sqlite3.ErrLocked:
+		result.Kind = internal.KindLockTimeout
+	case se.ExtendedCode == sqlite3.ErrConstraintUnique || se.ExtendedCode == sqlite3.ErrConstraintPrimaryKey:
+		result.Kind = internal.KindUniqueViolation
+		result.Table, result.Column = failedColumn(msg)
+	case se.ExtendedCode == sqlite3.ErrConstraintNotNull:
+		result.Kind = internal.KindNotNullViolation
+		result.Table, result.Column = failedColumn(msg)
+	case se.ExtendedCode == sqlite3.ErrConstraintForeignKey:
+		result.Kind = internal.KindForeignKeyViolation
+	case se.ExtendedCode == sqlite3.ErrConstraintCheck:
+		result.Kind = internal.KindCheckViolation
+		result.Constraint = detail(msg)
+	case strings.HasPrefix(msg, "no such table: "):
+		result.Kind = internal.KindTableNotFound
+		result.Table = strings.TrimPrefix(msg, "no such table: ")
+	default:
+		return nil
+	}
+	return result
+}
+
+// detail 取错误信息中冒号之后的部分
+func detail(msg string) string {
+	if idx := strings.Index(msg, ": "); idx >= 0 {
+		return msg[idx+2:]
+	}
+	return ""
+}
+
+// failedColumn 多列约束只取第一列
+func failedColumn(msg string) (table, column string) {
+	first := strings.SplitN(detail(msg), ",", 2)[0]
+	if idx := strings.Index(first, "."); idx >= 0 {
+		return first[:idx], first[idx+1:]
+	}
+	return "", first
+}
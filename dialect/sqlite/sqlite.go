@@ -21,15 +21,121 @@
 package sqlite
 
 import (
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/mattn/go-sqlite3"
 	"github.com/yhyzgn/glue/dialect"
+	"github.com/yhyzgn/glue/internal"
+	"reflect"
 )
 
+func init() {
+	dialect.Register("sqlite", &sqlite3.SQLiteDriver{}, func() internal.Dialect {
+		return Dialect()
+	})
+}
+
 type SQLite struct {
 	*dialect.Creator
 }
 
 func Dialect() *SQLite {
-	dialect.Current = &SQLite{dialect.New(new(sqlite))}
-	return dialect.Current.(*SQLite)
+	dl := &SQLite{dialect.New(new(sqlite))}
+	dl.Bind(dl)
+	return dl
+}
+
+func (*SQLite) SQLType(field *reflect.StructField) string {
+	return types.SQLType(field)
+}
+
+// Limit sqlite 的 OFFSET 必须跟在 LIMIT 之后，LIMIT -1 表示不限条数
+func (s *SQLite) Limit(cmd *internal.Command, limit, offset int) *internal.Command {
+	if limit > 0 || offset <= 0 {
+		return s.Creator.Limit(cmd, limit, offset)
+	}
+	return cmd.Clone().
+		Line("LIMIT -1").
+		Line("OFFSET " + s.Placeholder(len(cmd.Args())+1)).
+		Arguments(offset)
+}
+
+func (*SQLite) RowComparison() bool {
+	return true
+}
+
+// Identity sqlite 只有单列的 INTEGER PRIMARY KEY 可以自增
+func (*SQLite) Identity(table *internal.Definition, field *internal.Field) (string, bool) {
+	if len(table.PrimaryKeys) > 1 {
+		return field.SQLType + " NOT NULL", false
+	}
+	return "INTEGER PRIMARY KEY AUTOINCREMENT", true
+}
+
+// Comment sqlite 不支持列注释
+func (*SQLite) Comment(table string, field *internal.Field) (string, *internal.Command) {
+	return "", nil
+}
+
+// ModifyColumn sqlite 不能修改已有列的类型和可空性，只能重建表，返回 nil
+func (*SQLite) ModifyColumn(table, column, rename, tpy, comment string, notNull bool, defValue interface{}) []*internal.Command {
+	return nil
+}
+
+// sqlite 没有 INFORMATION_SCHEMA，存在性检查读取 sqlite_master 和 PRAGMA 表值函数，schema 为 main 或 ATTACH 的名称
+
+func (s *SQLite) HasTable(schema, name string) *internal.Command {
+	return internal.NewCommand("SELECT").
+		TabLine("COUNT(*)").
+		Line("FROM").
+		TabLine(s.Quote(schema) + ".sqlite_master").
+		Line("WHERE").
+		TabLine("type = 'table'").
+		TabLine("AND name = ?").
+		Arguments(name)
+}
+
+func (*SQLite) Columns(schema, table string) *internal.Command {
+	return internal.NewCommand("SELECT").
+		TabLine("*").
+		Line("FROM").
+		TabLine("pragma_table_info(?, ?)").
+		Line("ORDER BY").
+		TabLine("cid ASC").
+		Arguments(table, schema)
+}
+
+func (*SQLite) HasColumn(schema, table, column string) *internal.Command {
+	return internal.NewCommand("SELECT").
+		TabLine("COUNT(*)").
+		Line("FROM").
+		TabLine("pragma_table_info(?, ?)").
+		Line("WHERE").
+		TabLine("name = ?").
+		Arguments(table, schema, column)
+}
+
+func (*SQLite) HasIndex(schema, table, name string) *internal.Command {
+	return internal.NewCommand("SELECT").
+		TabLine("COUNT(*)").
+		Line("FROM").
+		TabLine("pragma_index_list(?, ?)").
+		Line("WHERE").
+		TabLine("name = ?").
+		Arguments(table, schema, name)
+}
+
+// HasForeignKey sqlite 不保存外键名，按 fk_表名_首列 比较，与 ForeignKeysQuery 一致，自定义名称的外键查不到
+func (*SQLite) HasForeignKey(schema, table, name string) *internal.Command {
+	return internal.NewCommand("SELECT").
+		TabLine("COUNT(*)").
+		Line("FROM").
+		TabLine("pragma_foreign_key_list(?, ?)").
+		Line("WHERE").
+		TabLine(`seq = 0`).
+		TabLine(`AND 'fk_' || ? || '_' || "from" = ?`).
+		Arguments(table, schema, table, name)
+}
+
+// AddForeignKey sqlite 不支持 ALTER TABLE ADD CONSTRAINT，外键只能在建表时声明，忽略
+func (*SQLite) AddForeignKey(table string, key *internal.ForeignKey) *internal.Command {
+	return nil
 }
@@ -0,0 +1,54 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-01-19 14:32
+// version: 1.0.0
+// desc   : 
+
+package sqlite
+
+import (
+	"fmt"
+	"github.com/yhyzgn/glue/dialect"
+)
+
+// sqlite 按类型亲和性存储，整数统一使用 INTEGER 以便自增主键生效
+var types = &dialect.Types{
+	Bool:    "BOOLEAN",
+	Int8:    "INTEGER",
+	Int16:   "INTEGER",
+	Int32:   "INTEGER",
+	Int64:   "INTEGER",
+	Uint8:   "INTEGER",
+	Uint16:  "INTEGER",
+	Uint32:  "INTEGER",
+	Uint64:  "INTEGER",
+	Float32: "REAL",
+	Float64: "REAL",
+	Time:    "DATETIME",
+	String: func(size int) string {
+		if size <= 0 {
+			return "TEXT"
+		}
+		return fmt.Sprintf("VARCHAR(%d)", size)
+	},
+	Bytes: func(size int) string {
+		return "BLOB"
+	},
+	Decimal: func(precision, scale int) string {
+		return fmt.Sprintf("DECIMAL(%d,%d)", precision, scale)
+	},
+}
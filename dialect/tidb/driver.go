@@ -0,0 +1,59 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-02-11 09:40
+// version: 1.0.0
+// desc   : 
+
+package tidb
+
+import (
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/yhyzgn/glue/dialect"
+	"github.com/yhyzgn/glue/internal"
+)
+
+// tidb 使用 mysql 协议和驱动，从 *sql.DB 查找时得到的是 mysql 方言
+func init() {
+	dialect.Register("tidb", nil, func() internal.Dialect {
+		return Dialect()
+	})
+}
+
+var quoter = dialect.Quoter{Open: "`", Close: "`"}
+
+type tidb struct {
+}
+
+func (*tidb) Name() string {
+	return "tidb"
+}
+
+func (*tidb) Driver() string {
+	return "mysql"
+}
+
+func (*tidb) Quote(key string) string {
+	return quoter.Quote(key)
+}
+
+func (*tidb) Placeholder(index int) string {
+	return "?"
+}
+
+func (*tidb) Database() string {
+	return "SELECT DATABASE()"
+}
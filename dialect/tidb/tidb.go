@@ -0,0 +1,66 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-02-11 10:05
+// version: 1.0.0
+// desc   : 
+
+package tidb
+
+import (
+	"github.com/yhyzgn/glue/dialect"
+	"github.com/yhyzgn/glue/dialect/mysql"
+	"github.com/yhyzgn/glue/internal"
+	"strings"
+)
+
+// TiDB 兼容 mysql 语法，自增主键使用 AUTO_RANDOM 打散写入热点，外键只解析不生效，因此不生成外键
+type TiDB struct {
+	*mysql.MySQL
+}
+
+func Dialect() *TiDB {
+	dl := &TiDB{&mysql.MySQL{Creator: dialect.New(new(tidb))}}
+	dl.Bind(dl)
+	return dl
+}
+
+// Identity AUTO_RANDOM 只能用于 BIGINT 主键，其他类型仍使用 AUTO_INCREMENT
+func (t *TiDB) Identity(table *internal.Definition, field *internal.Field) (string, bool) {
+	if strings.HasPrefix(strings.ToUpper(field.SQLType), "BIGINT") {
+		return field.SQLType + " NOT NULL AUTO_RANDOM", false
+	}
+	return t.MySQL.Identity(table, field)
+}
+
+func (t *TiDB) CreateTable(definition *internal.Definition) []*internal.Command {
+	if definition == nil {
+		return nil
+	}
+	withoutForeignKeys := *definition
+	withoutForeignKeys.ForeignKeys = nil
+	return t.MySQL.CreateTable(&withoutForeignKeys)
+}
+
+// AddIndex tidb 不支持 FULLTEXT 和 SPATIAL 索引，跳过 MySQL.AddIndex 直接使用通用实现，
+// 这两类索引按普通索引创建，唯一索引不受影响
+func (t *TiDB) AddIndex(table, name string, indexes []*internal.Index) *internal.Command {
+	return t.Creator.AddIndex(table, name, indexes)
+}
+
+func (*TiDB) AddForeignKey(table string, key *internal.ForeignKey) *internal.Command {
+	return nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-02-28 09:40
+// version: 1.0.0
+// desc   : 
+
+package tidb
+
+import (
+	"github.com/yhyzgn/glue/internal"
+	"github.com/yhyzgn/glue/primary"
+	"strings"
+	"testing"
+)
+
+type order struct {
+	internal.TableModel
+	ID     int64  `glue:"pk"`
+	UserID int64  `glue:"fk:user(id)"`
+	Note   string `glue:"size:255;index:idx_order_note"`
+}
+
+func (*order) TableName() string {
+	return "order"
+}
+
+func (*order) PrimaryStrategy() internal.Strategy {
+	return &primary.AutoIncrement{}
+}
+
+func TestCreateTable(t *testing.T) {
+	dl := Dialect()
+	def, err := internal.Parse(dl, &order{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	commands := dl.CreateTable(def)
+	if len(commands) != 2 {
+		t.Fatalf("unexpected commands count %d", len(commands))
+	}
+	if sql := commands[0].SQL(); !strings.Contains(sql, "`id` BIGINT NOT NULL AUTO_RANDOM,") || strings.Contains(sql, "FOREIGN KEY") {
+		t.Errorf("unexpected create table:\n%s", sql)
+	}
+	if sql := commands[1].SQL(); sql != "CREATE INDEX `idx_order_note` ON `order` (`note`)" {
+		t.Errorf("unexpected index: %s", sql)
+	}
+	if def.ForeignKeys["fk_order_user_id"] == nil {
+		t.Error("foreign keys of the definition should be kept")
+	}
+
+	// 非 BIGINT 主键不能使用 AUTO_RANDOM
+	if column, _ := dl.Identity(def, &internal.Field{SQLType: "INT"}); column != "INT NOT NULL AUTO_INCREMENT" {
+		t.Errorf("unexpected identity: %s", column)
+	}
+}
+
+func TestAddIndex(t *testing.T) {
+	dl := Dialect()
+	fulltext := []*internal.Index{{Name: "idx_order_note", Column: "note", Type: internal.IndexFullText}}
+	if cmd := dl.AddIndex("order", "idx_order_note", fulltext); cmd.SQL() != "CREATE INDEX `idx_order_note` ON `order` (`note`)" {
+		t.Errorf("unexpected fulltext index: %s", cmd.SQL())
+	}
+	if cmd := dl.AddForeignKey("order", &internal.ForeignKey{Name: "fk_order_user_id", Column: "user_id", Table: "user", Reference: "id"}); cmd != nil {
+		t.Errorf("unexpected foreign key: %s", cmd.SQL())
+	}
+}
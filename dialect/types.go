@@ -0,0 +1,167 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-01-19 11:05
+// version: 1.0.0
+// desc   : 
+
+package dialect
+
+import (
+	"database/sql"
+	"github.com/yhyzgn/glue/internal"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultStringSize 未指定 size 标签时字符串列的长度
+const DefaultStringSize = 255
+
+var (
+	timeType        = reflect.TypeOf(time.Time{})
+	nullStringType  = reflect.TypeOf(sql.NullString{})
+	nullInt64Type   = reflect.TypeOf(sql.NullInt64{})
+	nullInt32Type   = reflect.TypeOf(sql.NullInt32{})
+	nullFloat64Type = reflect.TypeOf(sql.NullFloat64{})
+	nullBoolType    = reflect.TypeOf(sql.NullBool{})
+	nullTimeType    = reflect.TypeOf(sql.NullTime{})
+)
+
+// Types Go 类型到数据库列类型的映射表，每种方言各自声明一份
+type Types struct {
+	Bool    string
+	Int8    string
+	Int16   string
+	Int32   string
+	Int64   string
+	Uint8   string
+	Uint16  string
+	Uint32  string
+	Uint64  string
+	Float32 string
+	Float64 string
+	Time    string
+	// size <= 0 表示不限长度
+	String  func(size int) string
+	Bytes   func(size int) string
+	Decimal func(precision, scale int) string
+}
+
+// SQLType 按字段类型和 glue 标签推导列类型，标签中的 type 优先
+//
+// 指针和 sql.Null* 类型按其基础类型处理，指针字段总是可空的，其余字段的可空性由 notnull 标签决定
+func (t *Types) SQLType(field *reflect.StructField) string {
+	if field == nil {
+		return ""
+	}
+	tag, err := internal.ParseTag(field.Tag.Get(internal.TagName))
+	if err != nil {
+		return ""
+	}
+	if tag.Has(internal.TagType) {
+		return tag.Get(internal.TagType)
+	}
+
+	tp := field.Type
+	for tp.Kind() == reflect.Ptr {
+		tp = tp.Elem()
+	}
+	if tag.Has(internal.TagDecimal) {
+		precision, scale, ok := parseDecimal(tag.Get(internal.TagDecimal))
+		if !ok {
+			return ""
+		}
+		return t.Decimal(precision, scale)
+	}
+
+	size := DefaultStringSize
+	if tag.Has(internal.TagSize) {
+		value, err := strconv.Atoi(tag.Get(internal.TagSize))
+		if err != nil {
+			return ""
+		}
+		size = value
+	}
+
+	switch tp {
+	case timeType, nullTimeType:
+		return t.Time
+	case nullStringType:
+		return t.String(size)
+	case nullInt64Type:
+		return t.Int64
+	case nullInt32Type:
+		return t.Int32
+	case nullFloat64Type:
+		return t.Float64
+	case nullBoolType:
+		return t.Bool
+	}
+
+	switch tp.Kind() {
+	case reflect.Bool:
+		return t.Bool
+	case reflect.Int8:
+		return t.Int8
+	case reflect.Int16:
+		return t.Int16
+	case reflect.Int32:
+		return t.Int32
+	case reflect.Int, reflect.Int64:
+		return t.Int64
+	case reflect.Uint8:
+		return t.Uint8
+	case reflect.Uint16:
+		return t.Uint16
+	case reflect.Uint32:
+		return t.Uint32
+	case reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		return t.Uint64
+	case reflect.Float32:
+		return t.Float32
+	case reflect.Float64:
+		return t.Float64
+	case reflect.String:
+		return t.String(size)
+	case reflect.Slice, reflect.Array:
+		if tp.Elem().Kind() == reflect.Uint8 {
+			if !tag.Has(internal.TagSize) {
+				size = 0
+			}
+			return t.Bytes(size)
+		}
+	}
+	return ""
+}
+
+func parseDecimal(value string) (precision, scale int, ok bool) {
+	parts := strings.Split(value, ",")
+	if len(parts) > 2 {
+		return
+	}
+	precision, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil || precision <= 0 {
+		return
+	}
+	if len(parts) == 2 {
+		if scale, err = strconv.Atoi(strings.TrimSpace(parts[1])); err != nil || scale < 0 || scale > precision {
+			return
+		}
+	}
+	return precision, scale, true
+}
@@ -0,0 +1,31 @@
+//go:build oracle
+// +build oracle
+
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-02-27 10:30
+// version: 1.0.0
+// desc   : 
+
+package dialect_test
+
+import "github.com/yhyzgn/glue/dialect/oracle"
+
+// oracle 依赖 oci8 的 cgo 构建，只在 oracle 标签下测试
+func init() {
+	sqlTypeCases = append(sqlTypeCases, sqlTypeCase{oracle.Dialect(), []string{"NUMBER(1)", "NUMBER(3)", "NUMBER(19)", "NUMBER(20)", "BINARY_FLOAT", "BINARY_DOUBLE", "VARCHAR2(255 CHAR)", "VARCHAR2(64 CHAR)", "CLOB", "BLOB", "TIMESTAMP", "VARCHAR2(255 CHAR)", "NUMBER(10)", "NUMBER(10,2)"}})
+}
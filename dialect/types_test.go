@@ -0,0 +1,130 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-01-19 15:30
+// version: 1.0.0
+// desc   : 
+
+package dialect_test
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/yhyzgn/glue/dialect"
+	"github.com/yhyzgn/glue/dialect/clickhouse"
+	"github.com/yhyzgn/glue/dialect/mssql"
+	"github.com/yhyzgn/glue/dialect/mysql"
+	"github.com/yhyzgn/glue/dialect/postgres"
+	"github.com/yhyzgn/glue/dialect/sqlite"
+	"github.com/yhyzgn/glue/internal"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var testTypes = &dialect.Types{
+	Bool:    "BOOL",
+	Int8:    "INT8",
+	Int16:   "INT16",
+	Int32:   "INT32",
+	Int64:   "INT64",
+	Uint8:   "UINT8",
+	Uint16:  "UINT16",
+	Uint32:  "UINT32",
+	Uint64:  "UINT64",
+	Float32: "FLOAT32",
+	Float64: "FLOAT64",
+	Time:    "TIME",
+	String: func(size int) string {
+		return fmt.Sprintf("STRING(%d)", size)
+	},
+	Bytes: func(size int) string {
+		return fmt.Sprintf("BYTES(%d)", size)
+	},
+	Decimal: func(precision, scale int) string {
+		return fmt.Sprintf("DECIMAL(%d,%d)", precision, scale)
+	},
+}
+
+func TestTypes_SQLType(t *testing.T) {
+	type model struct {
+		Flag     bool
+		Count    int
+		Small    *uint16
+		Ratio    float32
+		Name     string
+		Title    string `glue:"size:64"`
+		Data     []byte
+		At       time.Time
+		Nickname sql.NullString
+		Score    sql.NullInt64
+		Price    float64 `glue:"decimal:10,2"`
+		JSON     string  `glue:"type:JSON"`
+		Tags     []string
+	}
+	expected := []string{"BOOL", "INT64", "UINT16", "FLOAT32", "STRING(255)", "STRING(64)", "BYTES(0)", "TIME", "STRING(255)", "INT64", "DECIMAL(10,2)", "JSON", ""}
+
+	tp := reflect.TypeOf(model{})
+	for i := 0; i < tp.NumField(); i++ {
+		field := tp.Field(i)
+		if actual := testTypes.SQLType(&field); actual != expected[i] {
+			t.Errorf("SQLType of %s = %s, expected %s", field.Name, actual, expected[i])
+		}
+	}
+}
+
+// sqlTypeCase 方言对 TestSQLType 中模型字段的映射，按字段顺序排列
+type sqlTypeCase struct {
+	dialect  internal.Dialect
+	expected []string
+}
+
+var sqlTypeCases = []sqlTypeCase{
+	{mysql.Dialect(), []string{"TINYINT(1)", "TINYINT", "BIGINT", "BIGINT UNSIGNED", "FLOAT", "DOUBLE", "VARCHAR(255)", "VARCHAR(64)", "MEDIUMTEXT", "LONGBLOB", "DATETIME", "VARCHAR(255)", "INT", "DECIMAL(10,2)"}},
+	{postgres.Dialect(), []string{"BOOLEAN", "SMALLINT", "BIGINT", "NUMERIC(20)", "REAL", "DOUBLE PRECISION", "VARCHAR(255)", "VARCHAR(64)", "VARCHAR(100000)", "BYTEA", "TIMESTAMP WITH TIME ZONE", "VARCHAR(255)", "INTEGER", "NUMERIC(10,2)"}},
+	{sqlite.Dialect(), []string{"BOOLEAN", "INTEGER", "INTEGER", "INTEGER", "REAL", "REAL", "VARCHAR(255)", "VARCHAR(64)", "VARCHAR(100000)", "BLOB", "DATETIME", "VARCHAR(255)", "INTEGER", "DECIMAL(10,2)"}},
+	{mssql.Dialect(), []string{"BIT", "SMALLINT", "BIGINT", "NUMERIC(20,0)", "REAL", "FLOAT", "NVARCHAR(255)", "NVARCHAR(64)", "NVARCHAR(MAX)", "VARBINARY(MAX)", "DATETIME2", "NVARCHAR(255)", "INT", "DECIMAL(10,2)"}},
+	{clickhouse.Dialect(), []string{"UInt8", "Int8", "Int64", "UInt64", "Float32", "Float64", "String", "String", "String", "String", "DateTime", "String", "Int32", "Decimal(10, 2)"}},
+}
+
+func TestSQLType(t *testing.T) {
+	type model struct {
+		Flag     bool
+		Tiny     int8
+		Count    int
+		Size     uint64
+		Ratio    float32
+		Score    float64
+		Name     string
+		Title    string `glue:"size:64"`
+		Body     string `glue:"size:100000"`
+		Data     []byte
+		At       time.Time
+		Nickname *string
+		Age      sql.NullInt32
+		Price    float64 `glue:"decimal:10,2"`
+	}
+
+	tp := reflect.TypeOf(model{})
+	for _, cs := range sqlTypeCases {
+		for i := 0; i < tp.NumField(); i++ {
+			field := tp.Field(i)
+			if actual := cs.dialect.SQLType(&field); actual != cs.expected[i] {
+				t.Errorf("%s SQLType of %s = %s, expected %s", cs.dialect.Name(), field.Name, actual, cs.expected[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,306 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-01-23 10:20
+// version: 1.0.0
+// desc   : 
+
+package glue
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/yhyzgn/glue/dialect"
+	"github.com/yhyzgn/glue/internal"
+	"github.com/yhyzgn/glue/migrate"
+	"reflect"
+	"strconv"
+	"sync"
+)
+
+var ErrNoPrimaryKey = errors.New("glue: table has no primary key")
+
+type definitionKey struct {
+	dialect string
+	tp      reflect.Type
+}
+
+var definitions sync.Map
+
+// Legacy 将不支持 context 的执行器适配为 Executor，ctx 只在执行前检查，QueryRowContext 不检查 ctx
+func Legacy(executor internal.LegacyExecutor) Executor {
+	return internal.Legacy(executor)
+}
+
+func Exec(ctx context.Context, executor Executor, cmd *Command) (sql.Result, error) {
+	return executor.ExecContext(ctx, cmd.SQL(), cmd.Args()...)
+}
+
+func Query(ctx context.Context, executor Executor, cmd *Command) (*sql.Rows, error) {
+	return executor.QueryContext(ctx, cmd.SQL(), cmd.Args()...)
+}
+
+// Insert 插入一条记录，由数据库生成的主键会回写到 table 的主键字段，
+// 方言不能通过 RETURNING 等子句取回主键时只支持自增主键，其余由数据库生成的主键返回错误，
+// Insert、Update、Delete 返回的数据库错误经 dialect.Translate 转换，可用 errors.Is 判断分类
+func Insert(ctx context.Context, dialect Dialect, executor Executor, table Table) (sql.Result, error) {
+	def, model, err := prepare(dialect, table)
+	if err != nil {
+		return nil, err
+	}
+	table.BeforeInsert()
+
+	value := &internal.ExecValue{Table: def.TableName, Type: internal.ExecInsert}
+	var generated *Field
+	for _, field := range def.Fields {
+		fv := internal.FieldOf(model, field)
+		if field.IsPrimary && len(def.PrimaryKeys) == 1 && (!fv.IsValid() || fv.IsZero()) && def.Strategy != nil {
+			// 主键未赋值时由主键策略生成，策略返回 nil 表示由数据库生成
+			key := def.Strategy.Primary()
+			if key == nil {
+				generated = field
+				continue
+			}
+			// 表达式由数据库求值，生成的主键与自增主键一样取回
+			if expr, ok := key.(internal.Expression); ok {
+				if _, err := expr.Expression(dialect); err != nil {
+					return nil, err
+				}
+				generated = field
+				value.Columns = append(value.Columns, field.Column)
+				value.Values = append(value.Values, expr)
+				continue
+			}
+			if err := assign(model, field, key); err != nil {
+				return nil, err
+			}
+			fv = internal.FieldOf(model, field)
+		}
+		value.Columns = append(value.Columns, field.Column)
+		value.Values = append(value.Values, valueOf(fv))
+	}
+	if generated != nil {
+		value.Primary = generated.Column
+	}
+
+	cmd := dialect.Insert(value)
+	returning := cmd != nil && cmd.GeneratedKey() != ""
+	if returning {
+		// 按主键字段的类型取回，直接写入字段
+		cmd.Into(internal.SettableOf(model, generated).Addr().Interface())
+	} else if generated != nil && !identity(dialect, def, generated) {
+		return nil, fmt.Errorf("glue: dialect %s can not return the generated key %s.%s", dialect.Name(), def.TableName, generated.Column)
+	}
+	result, err := dialect.InsertExecutor(ctx, executor, cmd)
+	if err != nil || generated == nil || returning {
+		return result, dialect.Translate(err)
+	}
+	// 自增主键通过 LastInsertId 取回
+	id, err := result.LastInsertId()
+	if err != nil {
+		return result, err
+	}
+	return result, assign(model, generated, id)
+}
+
+// Update 按主键更新除主键外的所有列
+func Update(ctx context.Context, dialect Dialect, executor Executor, table Table) (sql.Result, error) {
+	def, model, err := prepare(dialect, table)
+	if err != nil {
+		return nil, err
+	}
+	if len(def.PrimaryKeys) == 0 {
+		return nil, ErrNoPrimaryKey
+	}
+	table.BeforeUpdate()
+
+	value := &internal.ExecValue{Table: def.TableName, Type: internal.ExecUpdate}
+	for _, field := range def.Fields {
+		if !field.IsPrimary {
+			value.Columns = append(value.Columns, field.Column)
+			value.Values = append(value.Values, valueOf(internal.FieldOf(model, field)))
+		}
+	}
+	value.Where = primaryCondition(def, model)
+	result, err := dialect.UpdateExecutor(ctx, executor, dialect.Update(value))
+	return result, dialect.Translate(err)
+}
+
+// Delete 按主键删除
+func Delete(ctx context.Context, dialect Dialect, executor Executor, table Table) (sql.Result, error) {
+	def, model, err := prepare(dialect, table)
+	if err != nil {
+		return nil, err
+	}
+	if len(def.PrimaryKeys) == 0 {
+		return nil, ErrNoPrimaryKey
+	}
+	value := &internal.ExecValue{Table: def.TableName, Where: primaryCondition(def, model), Type: internal.ExecDelete}
+	result, err := dialect.UpdateExecutor(ctx, executor, dialect.Delete(value))
+	return result, dialect.Translate(err)
+}
+
+// Define 解析 table 的表定义，同一方言下同类型的结果会被缓存
+func Define(dialect Dialect, table Table) (*Definition, error) {
+	key := definitionKey{dialect: dialect.Name(), tp: reflect.TypeOf(table)}
+	cached, ok := definitions.Load(key)
+	if !ok {
+		def, err := internal.Parse(dialect, table)
+		if err != nil {
+			return nil, err
+		}
+		cached, _ = definitions.LoadOrStore(key, def)
+	}
+	// 主键策略可能因实例而异，每次都重新获取
+	def := *cached.(*Definition)
+	def.Strategy = table.PrimaryStrategy()
+	return &def, nil
+}
+
+// AutoMigrate 按模型同步表结构，安全模式下不会删除多余的列
+func AutoMigrate(ctx context.Context, dialect Dialect, executor Executor, tables ...Table) error {
+	defs, err := defineAll(dialect, tables)
+	if err != nil {
+		return err
+	}
+	return migrate.AutoMigrate(ctx, dialect, executor, defs...)
+}
+
+// MigratePlan 计算同步表结构所需的变更但不执行，可通过 Script 导出 sql 脚本供审阅
+func MigratePlan(ctx context.Context, dialect Dialect, executor Executor, tables ...Table) (*migrate.Plan, error) {
+	defs, err := defineAll(dialect, tables)
+	if err != nil {
+		return nil, err
+	}
+	return migrate.New(dialect, executor).Plan(ctx, defs...)
+}
+
+// HasTable 判断当前 schema 中是否存在表，schema 由 dialect.Schema 解析
+func HasTable(ctx context.Context, dialect Dialect, executor Executor, name string) (bool, error) {
+	return exists(ctx, dialect, executor, func(schema string) *Command {
+		return dialect.HasTable(schema, name)
+	})
+}
+
+func HasColumn(ctx context.Context, dialect Dialect, executor Executor, table, column string) (bool, error) {
+	return exists(ctx, dialect, executor, func(schema string) *Command {
+		return dialect.HasColumn(schema, table, column)
+	})
+}
+
+func HasIndex(ctx context.Context, dialect Dialect, executor Executor, table, name string) (bool, error) {
+	return exists(ctx, dialect, executor, func(schema string) *Command {
+		return dialect.HasIndex(schema, table, name)
+	})
+}
+
+func HasForeignKey(ctx context.Context, dialect Dialect, executor Executor, table, name string) (bool, error) {
+	return exists(ctx, dialect, executor, func(schema string) *Command {
+		return dialect.HasForeignKey(schema, table, name)
+	})
+}
+
+func exists(ctx context.Context, dl Dialect, executor Executor, build func(schema string) *Command) (bool, error) {
+	return dialect.Exists(ctx, dl, executor, build)
+}
+
+// identity 判断 field 是否为自增主键，只有自增主键能通过 LastInsertId 取回
+func identity(dl Dialect, def *Definition, field *Field) bool {
+	identity := dialect.IdentityOf(dl, def)
+	return identity != nil && identity.Column == field.Column
+}
+
+func defineAll(dialect Dialect, tables []Table) ([]*Definition, error) {
+	defs := make([]*Definition, len(tables))
+	for i, table := range tables {
+		def, err := Define(dialect, table)
+		if err != nil {
+			return nil, err
+		}
+		defs[i] = def
+	}
+	return defs, nil
+}
+
+func prepare(dialect Dialect, table Table) (*Definition, reflect.Value, error) {
+	if dialect == nil {
+		return nil, reflect.Value{}, errors.New("glue: dialect is nil")
+	}
+	model := reflect.ValueOf(table)
+	if model.Kind() != reflect.Ptr || model.IsNil() {
+		return nil, reflect.Value{}, fmt.Errorf("glue: table model must be a non-nil pointer, got %T", table)
+	}
+	def, err := Define(dialect, table)
+	if err != nil {
+		return nil, reflect.Value{}, err
+	}
+	return def, model.Elem(), nil
+}
+
+func primaryCondition(def *Definition, model reflect.Value) Condition {
+	conditions := make([]Condition, len(def.PrimaryKeys))
+	for i, field := range def.PrimaryKeys {
+		conditions[i] = internal.Eq(field.Column, valueOf(internal.FieldOf(model, field)))
+	}
+	return internal.And(conditions...)
+}
+
+func valueOf(value reflect.Value) interface{} {
+	if !value.IsValid() {
+		return nil
+	}
+	return value.Interface()
+}
+
+// assign 将 key 写入字段，支持数值之间、数值与字符串之间的转换
+func assign(model reflect.Value, field *Field, key interface{}) error {
+	target := internal.SettableOf(model, field)
+	if target.Kind() == reflect.Ptr {
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+		target = target.Elem()
+	}
+	value := reflect.ValueOf(key)
+	switch {
+	case value.Type().AssignableTo(target.Type()):
+		target.Set(value)
+	case target.Kind() == reflect.String && value.Kind() == reflect.Int64:
+		target.SetString(strconv.FormatInt(value.Int(), 10))
+	case value.Type().ConvertibleTo(target.Type()) && target.Kind() != reflect.String:
+		target.Set(value.Convert(target.Type()))
+	default:
+		return fmt.Errorf("glue: can not assign %T to field %s", key, field.Name)
+	}
+	return nil
+}
+
+const (
+	UnknownIgnore = internal.UnknownIgnore
+	UnknownError  = internal.UnknownError
+)
+
+// ScanOne 扫描第一行到 dest，没有数据时返回 sql.ErrNoRows，rows 会被关闭
+func ScanOne(rows *sql.Rows, dest interface{}, unknown UnknownColumn) error {
+	return internal.ScanOne(rows, dest, unknown)
+}
+
+// ScanAll 扫描所有行到 dest 切片，rows 会被关闭
+func ScanAll(rows *sql.Rows, dest interface{}, unknown UnknownColumn) error {
+	return internal.ScanAll(rows, dest, unknown)
+}
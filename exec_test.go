@@ -0,0 +1,537 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-01-23 15:40
+// version: 1.0.0
+// desc   : 
+
+package glue
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	mssqldb "github.com/denisenkom/go-mssqldb"
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+	"github.com/yhyzgn/glue/dialect/cockroach"
+	"github.com/yhyzgn/glue/dialect/mariadb"
+	"github.com/yhyzgn/glue/dialect/mssql"
+	"github.com/yhyzgn/glue/dialect/mysql"
+	"github.com/yhyzgn/glue/dialect/postgres"
+	"github.com/yhyzgn/glue/dialect/sqlite"
+	"github.com/yhyzgn/glue/internal"
+	"github.com/yhyzgn/glue/primary"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+type account struct {
+	TableModel
+	ID   int64  `glue:"pk"`
+	Name string `glue:"notnull"`
+	Age  int
+}
+
+func (*account) TableName() string {
+	return "account"
+}
+
+func (*account) PrimaryStrategy() Strategy {
+	return &primary.AutoIncrement{}
+}
+
+func openSQLite(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec("CREATE TABLE account (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NOT NULL, age INTEGER)"); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestInsertUpdateDelete(t *testing.T) {
+	db := openSQLite(t)
+	defer db.Close()
+	dl := sqlite.Dialect()
+
+	for i, name := range []string{"glue", "yhyzgn"} {
+		acc := &account{Name: name, Age: 18}
+		if _, err := Insert(context.Background(), dl, db, acc); err != nil {
+			t.Fatal(err)
+		}
+		if acc.ID != int64(i+1) {
+			t.Fatalf("primary key not written back: %d", acc.ID)
+		}
+	}
+
+	acc := &account{ID: 1, Name: "glue", Age: 20}
+	if result, err := Update(context.Background(), dl, db, acc); err != nil {
+		t.Fatal(err)
+	} else if rows, _ := result.RowsAffected(); rows != 1 {
+		t.Fatalf("unexpected rows affected: %d", rows)
+	}
+	var age int
+	if err := db.QueryRow("SELECT age FROM account WHERE id = 1").Scan(&age); err != nil || age != 20 {
+		t.Fatalf("update failed: %v %d", err, age)
+	}
+
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Delete(context.Background(), dl, conn, acc); err != nil {
+		t.Fatal(err)
+	}
+	_ = conn.Close()
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM account").Scan(&count); err != nil || count != 1 {
+		t.Fatalf("delete failed: %v %d", err, count)
+	}
+}
+
+func TestInsertCanceled(t *testing.T) {
+	db := openSQLite(t)
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, executor := range []Executor{db, Legacy(db)} {
+		if _, err := Insert(ctx, sqlite.Dialect(), executor, &account{Name: "glue"}); err != context.Canceled {
+			t.Fatalf("expect context.Canceled, got %v", err)
+		}
+	}
+}
+
+type Profile struct {
+	Age int
+}
+
+type summary struct {
+	*Profile
+	ID       int64
+	Nickname sql.NullString `glue:"column:name"`
+}
+
+func TestScan(t *testing.T) {
+	db := openSQLite(t)
+	defer db.Close()
+	ctx := context.Background()
+	for _, name := range []string{"glue", "yhyzgn"} {
+		if _, err := Insert(ctx, sqlite.Dialect(), db, &account{Name: name, Age: 18}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rows, err := db.Query("SELECT id, name, age FROM account ORDER BY id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var accounts []*account
+	if err := ScanAll(rows, &accounts, UnknownError); err != nil {
+		t.Fatal(err)
+	}
+	if len(accounts) != 2 || accounts[1].ID != 2 || accounts[1].Name != "yhyzgn" || accounts[1].Age != 18 {
+		t.Fatalf("unexpected accounts: %v", accounts)
+	}
+
+	rows, _ = db.Query("SELECT id, name, age FROM account WHERE id = 2")
+	var sm summary
+	if err := ScanOne(rows, &sm, UnknownError); err != nil {
+		t.Fatal(err)
+	}
+	if sm.ID != 2 || sm.Nickname.String != "yhyzgn" || sm.Profile == nil || sm.Age != 18 {
+		t.Fatalf("unexpected summary: %+v", sm)
+	}
+
+	rows, _ = db.Query("SELECT id, name, 1 AS extra FROM account")
+	if err := ScanOne(rows, &account{}, UnknownError); err == nil {
+		t.Fatal("expect error of unknown column")
+	}
+	rows, _ = db.Query("SELECT id, name, 1 AS extra FROM account")
+	if err := ScanOne(rows, &account{}, UnknownIgnore); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, _ = db.Query("SELECT id, name FROM account ORDER BY id")
+	var maps []map[string]interface{}
+	if err := ScanAll(rows, &maps, UnknownIgnore); err != nil || len(maps) != 2 || maps[0]["name"] != "glue" {
+		t.Fatalf("unexpected maps: %v %v", maps, err)
+	}
+
+	rows, _ = db.Query("SELECT COUNT(*) FROM account")
+	var count int
+	if err := ScanOne(rows, &count, UnknownIgnore); err != nil || count != 2 {
+		t.Fatalf("unexpected count: %d %v", count, err)
+	}
+
+	rows, _ = db.Query("SELECT id FROM account WHERE id = 100")
+	if err := ScanOne(rows, &count, UnknownIgnore); err != sql.ErrNoRows {
+		t.Fatalf("expect sql.ErrNoRows, got %v", err)
+	}
+}
+
+func TestWithTx(t *testing.T) {
+	db := openSQLite(t)
+	defer db.Close()
+	ctx := context.Background()
+	dl := sqlite.Dialect()
+	count := func() (n int) {
+		_ = db.QueryRow("SELECT COUNT(*) FROM account").Scan(&n)
+		return
+	}
+
+	err := WithTx(ctx, db, nil, func(tx *Tx) error {
+		if _, err := Insert(tx.Context(), tx.Dialect(), tx, &account{Name: "outer"}); err != nil {
+			return err
+		}
+		// 嵌套调用失败只回滚到保存点
+		err := WithTx(tx.Context(), db, nil, func(tx *Tx) error {
+			if _, err := Insert(tx.Context(), dl, tx, &account{Name: "inner"}); err != nil {
+				return err
+			}
+			return errors.New("inner failed")
+		})
+		if err == nil || err.Error() != "inner failed" {
+			t.Errorf("unexpected nested error: %v", err)
+		}
+		// 嵌套调用的 ctx 已取消时不创建保存点，也不执行 fn
+		cancelled, cancel := context.WithCancel(tx.Context())
+		cancel()
+		err = WithTx(cancelled, db, nil, func(tx *Tx) error {
+			t.Error("fn should not run with a cancelled context")
+			return nil
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expect context.Canceled, got %v", err)
+		}
+		return WithTx(tx.Context(), db, nil, func(tx *Tx) error {
+			_, err := Insert(tx.Context(), dl, tx, &account{Name: "released"})
+			return err
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := count(); n != 2 {
+		t.Fatalf("unexpected committed rows: %d", n)
+	}
+
+	err = WithTx(ctx, db, &TxOptions{Dialect: dl, Isolation: sql.LevelSerializable}, func(tx *Tx) error {
+		if _, err := Insert(tx.Context(), dl, tx, &account{Name: "panic"}); err != nil {
+			return err
+		}
+		panic("boom")
+	})
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("panic should be recovered as error: %v", err)
+	}
+	if n := count(); n != 2 {
+		t.Fatalf("panicked transaction should be rolled back, got %d rows", n)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	cases := []struct {
+		dialect Dialect
+		err     error
+		kind    ErrorKind
+	}{
+		{postgres.Dialect(), &pq.Error{Code: "40001"}, KindSerialization},
+		{postgres.Dialect(), fmt.Errorf("wrapped: %w", &pq.Error{Code: "40P01"}), KindDeadlock},
+		{postgres.Dialect(), &pq.Error{Code: "42601"}, KindUnknown},
+		{cockroach.Dialect(), &pq.Error{Code: "40001"}, KindSerialization},
+		{mysql.Dialect(), &mysqldriver.MySQLError{Number: 1213}, KindDeadlock},
+		{mariadb.Dialect(), &mysqldriver.MySQLError{Number: 1205}, KindLockTimeout},
+		{mssql.Dialect(), mssqldb.Error{Number: 1205}, KindDeadlock},
+		{sqlite.Dialect(), sqlite3.Error{Code: sqlite3.ErrBusy}, KindLockTimeout},
+		{sqlite.Dialect(), errors.New("ORA-00060: deadlock detected"), KindUnknown},
+	}
+	for _, cs := range cases {
+		if kind := cs.dialect.Classify(cs.err); kind != cs.kind {
+			t.Errorf("unexpected %s kind of %v: %s", cs.dialect.Name(), cs.err, kind)
+		}
+	}
+}
+
+func TestRetry(t *testing.T) {
+	ctx := context.Background()
+	dl := postgres.Dialect()
+	policy := &RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}
+
+	attempts := 0
+	err := Retry(ctx, dl, policy, func() error {
+		if attempts++; attempts < 3 {
+			return &pq.Error{Code: "40001"}
+		}
+		return nil
+	})
+	if err != nil || attempts != 3 {
+		t.Errorf("unexpected retry result %v after %d attempts", err, attempts)
+	}
+
+	attempts = 0
+	err = Retry(ctx, dl, policy, func() error {
+		attempts++
+		return &pq.Error{Code: "40P01"}
+	})
+	if dl.Classify(err) != KindDeadlock || attempts != 3 {
+		t.Errorf("unexpected exhausted retry %v after %d attempts", err, attempts)
+	}
+
+	attempts = 0
+	err = Retry(ctx, dl, policy, func() error {
+		attempts++
+		return errors.New("syntax error")
+	})
+	if err == nil || attempts != 1 {
+		t.Errorf("non retryable error should not be retried, %d attempts", attempts)
+	}
+
+	attempts = 0
+	err = Retry(ctx, dl, &RetryPolicy{MaxAttempts: 3, Backoff: -time.Millisecond}, func() error {
+		attempts++
+		return nil
+	})
+	if err == nil || attempts != 0 {
+		t.Errorf("negative backoff should be rejected before running, %d attempts", attempts)
+	}
+
+	// 翻倍后不超过上限，也不会溢出成负数
+	for _, cs := range []struct{ backoff, limit, next time.Duration }{
+		{time.Millisecond, time.Second, 2 * time.Millisecond},
+		{600 * time.Millisecond, time.Second, time.Second},
+		{math.MaxInt64 / 2, math.MaxInt64, math.MaxInt64 - 1},
+		{math.MaxInt64/2 + 1, math.MaxInt64, math.MaxInt64},
+	} {
+		if next := nextBackoff(cs.backoff, cs.limit); next != cs.next {
+			t.Errorf("unexpected next backoff of %v: %v", cs.backoff, next)
+		}
+	}
+
+	// 事务整体重试，每次都是新事务
+	db := openSQLite(t)
+	defer db.Close()
+	attempts = 0
+	err = WithTx(ctx, db, &TxOptions{Retry: policy}, func(tx *Tx) error {
+		if _, err := Insert(tx.Context(), tx.Dialect(), tx, &account{Name: "retry"}); err != nil {
+			return err
+		}
+		if attempts++; attempts < 2 {
+			return sqlite3.Error{Code: sqlite3.ErrBusy}
+		}
+		return nil
+	})
+	var count int
+	_ = db.QueryRow("SELECT COUNT(*) FROM account").Scan(&count)
+	if err != nil || attempts != 2 || count != 1 {
+		t.Errorf("unexpected transaction retry %v: %d attempts, %d rows", err, attempts, count)
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	db := openSQLite(t)
+	defer db.Close()
+	ctx := context.Background()
+	dl := sqlite.Dialect()
+	if _, err := db.Exec("CREATE UNIQUE INDEX uk_account_name ON account (name)"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Insert(ctx, dl, db, &account{Name: "glue"}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := Insert(ctx, dl, db, &account{Name: "glue"})
+	var ge *Error
+	if !errors.Is(err, ErrUniqueViolation) || !errors.As(err, &ge) || ge.Table != "account" || ge.Column != "name" {
+		t.Errorf("unexpected unique violation: %#v", err)
+	}
+	var se sqlite3.Error
+	if !errors.As(err, &se) {
+		t.Errorf("driver error should be kept: %v", err)
+	}
+
+	_, err = db.Exec("INSERT INTO account (name) VALUES (NULL)")
+	if err = dl.Translate(err); !errors.Is(err, ErrNotNullViolation) || errors.Is(err, ErrUniqueViolation) {
+		t.Errorf("unexpected not null violation: %v", err)
+	}
+	_, err = db.Exec("SELECT * FROM missing")
+	if err = dl.Translate(err); !errors.As(err, &ge) || ge.Kind != KindTableNotFound || ge.Table != "missing" {
+		t.Errorf("unexpected table not found: %v", err)
+	}
+	if err = dl.Translate(db.QueryRow("SELECT id FROM account WHERE id = 0").Scan(new(int))); !errors.Is(err, ErrNoRows) || !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("unexpected no rows: %v", err)
+	}
+
+	cases := []struct {
+		dialect    Dialect
+		err        error
+		kind       ErrorKind
+		constraint string
+		column     string
+	}{
+		{mysql.Dialect(), &mysqldriver.MySQLError{Number: 1062, Message: "Duplicate entry 'glue' for key 'account.uk_account_name'"}, KindUniqueViolation, "uk_account_name", ""},
+		{mysql.Dialect(), &mysqldriver.MySQLError{Number: 1452, Message: "Cannot add or update a child row: a foreign key constraint fails (`app`.`member`, CONSTRAINT `fk_member_group_id` FOREIGN KEY (`group_id`) REFERENCES `groups` (`id`))"}, KindForeignKeyViolation, "fk_member_group_id", "group_id"},
+		{mysql.Dialect(), &mysqldriver.MySQLError{Number: 1048, Message: "Column 'name' cannot be null"}, KindNotNullViolation, "", "name"},
+		{mysql.Dialect(), &mysqldriver.MySQLError{Number: 3819, Message: "Check constraint 'chk_age' is violated."}, KindCheckViolation, "chk_age", ""},
+		{postgres.Dialect(), &pq.Error{Code: "23505", Constraint: "uk_account_name", Table: "account"}, KindUniqueViolation, "uk_account_name", ""},
+		{postgres.Dialect(), &pq.Error{Code: "23502", Column: "name"}, KindNotNullViolation, "", "name"},
+		{mssql.Dialect(), mssqldb.Error{Number: 2601, Message: "Cannot insert duplicate key row in object 'dbo.account' with unique index 'uk_account_name'. The duplicate key value is (glue)."}, KindUniqueViolation, "uk_account_name", ""},
+		{mssql.Dialect(), mssqldb.Error{Number: 547, Message: `The INSERT statement conflicted with the CHECK constraint "chk_age". The conflict occurred in database "app", table "dbo.member", column 'age'.`}, KindCheckViolation, "chk_age", ""},
+		{mssql.Dialect(), mssqldb.Error{Number: 515, Message: "Cannot insert the value NULL into column 'name', table 'app.dbo.account'; column does not allow nulls. INSERT fails."}, KindNotNullViolation, "", "name"},
+	}
+	for _, cs := range cases {
+		if !errors.As(cs.dialect.Translate(cs.err), &ge) || ge.Kind != cs.kind || ge.Constraint != cs.constraint || ge.Column != cs.column {
+			t.Errorf("unexpected %s translation of %v: %#v", cs.dialect.Name(), cs.err, ge)
+		}
+	}
+}
+
+type token struct {
+	TableModel
+	ID       string `glue:"pk"`
+	Name     string
+	strategy Strategy
+}
+
+func (*token) TableName() string {
+	return "token"
+}
+
+func (t *token) PrimaryStrategy() Strategy {
+	return t.strategy
+}
+
+func TestInsertStrategy(t *testing.T) {
+	db := openSQLite(t)
+	defer db.Close()
+	ctx := context.Background()
+	dl := sqlite.Dialect()
+	if _, err := db.Exec("CREATE TABLE token (id TEXT PRIMARY KEY, name TEXT)"); err != nil {
+		t.Fatal(err)
+	}
+
+	sf, err := primary.NewSnowflake(primary.SnowflakeConfig{Worker: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, strategy := range []Strategy{&primary.UUID{Version: primary.V7}, &primary.ULID{}, sf} {
+		tk := &token{Name: "glue", strategy: strategy}
+		if _, err := Insert(ctx, dl, db, tk); err != nil {
+			t.Fatal(err)
+		}
+		var name string
+		if tk.ID == "" || db.QueryRow("SELECT name FROM token WHERE id = ?", tk.ID).Scan(&name) != nil {
+			t.Errorf("primary key %q of %T not written", tk.ID, strategy)
+		}
+	}
+	if _, err := Insert(ctx, dl, db, &token{strategy: &primary.Sequence{Name: "token_seq"}}); err == nil || !strings.Contains(err.Error(), "sequences") {
+		t.Errorf("sqlite should not support sequences: %v", err)
+	}
+
+	value := &internal.ExecValue{
+		Table:   "token",
+		Columns: []string{"id", "name"},
+		Values:  []interface{}{(&primary.Sequence{Name: "token_seq"}).Primary(), "glue"},
+		Primary: "id",
+		Type:    internal.ExecInsert,
+	}
+	if cmd := dl.Insert(value); cmd != nil {
+		t.Errorf("sqlite insert with a sequence should be nil: %s", cmd.SQL())
+	}
+	cmd := postgres.Dialect().Insert(value)
+	if cmd.SQL() != `INSERT INTO "token" ("id", "name") VALUES (nextval('"token_seq"'), $1) RETURNING "id"` || len(cmd.Args()) != 1 {
+		t.Errorf("unexpected postgres insert: %s %v", cmd.SQL(), cmd.Args())
+	}
+	if sql := mssql.Dialect().Insert(value).SQL(); !strings.Contains(sql, "NEXT VALUE FOR [token_seq]") {
+		t.Errorf("unexpected mssql insert: %s", sql)
+	}
+}
+
+// returningSQLite 模拟支持 RETURNING 的方言，sqlite 3.35 之前不支持该子句，由 returningExecutor 改写
+type returningSQLite struct {
+	*sqlite.SQLite
+}
+
+func (r *returningSQLite) Returning(column string, index int) (output, suffix string) {
+	return "", "RETURNING " + r.Quote(column)
+}
+
+// returningExecutor 去掉 RETURNING 子句执行插入，再按 rowid 查询生成的主键
+type returningExecutor struct {
+	*sql.DB
+}
+
+func (r *returningExecutor) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	idx := strings.Index(query, " RETURNING ")
+	if idx < 0 {
+		return r.DB.QueryRowContext(ctx, query, args...)
+	}
+	if _, err := r.DB.ExecContext(ctx, query[:idx], args...); err != nil {
+		return r.DB.QueryRowContext(ctx, "SELECT NULL WHERE 0")
+	}
+	table := strings.Fields(query)[2]
+	return r.DB.QueryRowContext(ctx, "SELECT "+query[idx+len(" RETURNING "):]+" FROM "+table+" WHERE rowid = last_insert_rowid()")
+}
+
+func TestInsertGeneratedKey(t *testing.T) {
+	db := openSQLite(t)
+	defer db.Close()
+	ctx := context.Background()
+	if _, err := db.Exec("CREATE TABLE token (id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(16)))), name TEXT)"); err != nil {
+		t.Fatal(err)
+	}
+
+	// sqlite 只能通过 LastInsertId 取回 rowid，不能用于非自增主键
+	tk := &token{Name: "glue", strategy: &uuidDefault{}}
+	if _, err := Insert(ctx, sqlite.Dialect(), db, tk); err == nil || !strings.Contains(err.Error(), "generated key") {
+		t.Errorf("sqlite should not return a default key: %v", err)
+	}
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM token").Scan(&count); err != nil || count != 0 {
+		t.Errorf("rejected insert should not be executed: %v %d", err, count)
+	}
+
+	dl := &returningSQLite{sqlite.Dialect()}
+	dl.Bind(dl)
+	result, err := Insert(ctx, dl, &returningExecutor{db}, tk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var name string
+	if len(tk.ID) != 32 || db.QueryRow("SELECT name FROM token WHERE id = ?", tk.ID).Scan(&name) != nil || name != "glue" {
+		t.Errorf("generated key %q not written back", tk.ID)
+	}
+	if id, _ := result.LastInsertId(); id != 0 {
+		t.Errorf("text key should not be reported as last insert id: %d", id)
+	}
+
+	// 自增主键同样由 RETURNING 取回，整数主键作为 LastInsertId
+	acc := &account{Name: "glue"}
+	if result, err = Insert(ctx, dl, &returningExecutor{db}, acc); err != nil {
+		t.Fatal(err)
+	}
+	if id, _ := result.LastInsertId(); acc.ID != 1 || id != 1 {
+		t.Errorf("unexpected generated key: %d %d", acc.ID, id)
+	}
+}
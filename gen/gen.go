@@ -0,0 +1,314 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-02-03 10:20
+// version: 1.0.0
+// desc   : 
+
+package gen
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/yhyzgn/glue/internal"
+	"go/format"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Config 生成选项，Tables 为空时生成库中所有表
+type Config struct {
+	Package string
+	Tables  []string
+}
+
+// File 生成的一个源文件
+type File struct {
+	Name   string
+	Source []byte
+}
+
+// initialisms 按 Go 命名习惯整体大写的缩写
+var initialisms = map[string]bool{
+	"api": true, "html": true, "http": true, "https": true, "id": true, "ip": true, "json": true,
+	"sql": true, "uid": true, "url": true, "uri": true, "uuid": true, "xml": true,
+}
+
+// Generate 读取数据库中的表结构，每张表生成一个嵌入了 TableModel 的结构体源文件
+func Generate(ctx context.Context, dialect internal.Dialect, executor internal.Executor, config *Config) ([]*File, error) {
+	if config == nil {
+		config = new(Config)
+	}
+	pkg := config.Package
+	if pkg == "" {
+		pkg = "model"
+	}
+	tables := config.Tables
+	if len(tables) == 0 {
+		var err error
+		if tables, err = dialect.Tables(ctx, executor); err != nil {
+			return nil, err
+		}
+	}
+	files := make([]*File, 0, len(tables))
+	generated := make(map[string]string)
+	for _, table := range tables {
+		info, err := dialect.Inspect(ctx, executor, table)
+		if err != nil {
+			return nil, err
+		}
+		if info == nil {
+			return nil, fmt.Errorf("glue: table '%s' does not exist", table)
+		}
+		// 结构体名相同的表生成的类型和文件都会冲突，如只有大小写不同的表名
+		name := exportedName(info.Name)
+		if other, ok := generated[name]; ok {
+			return nil, fmt.Errorf("glue: tables '%s' and '%s' both generate model %s", other, info.Name, name)
+		}
+		generated[name] = info.Name
+		source, err := Model(pkg, info)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, &File{Name: fileName(name), Source: source})
+	}
+	return files, nil
+}
+
+// Model 根据表结构生成结构体源码，列类型保存在 type 标签中，以便按原类型建表
+func Model(pkg string, info *internal.TableInfo) ([]byte, error) {
+	name := exportedName(info.Name)
+	tags := columnTags(info)
+	identity, hasTime := false, false
+	fields := make(map[string]bool)
+
+	var body bytes.Buffer
+	for _, column := range info.Columns {
+		field := exportedName(column.Name)
+		for fields[field] {
+			field += "_"
+		}
+		fields[field] = true
+
+		tp := goType(column)
+		if tp == "time.Time" {
+			hasTime = true
+		}
+		// []byte 本身可以为 nil，无需使用指针
+		if column.Nullable && !tags[column.Name].primary && tp != "[]byte" {
+			tp = "*" + tp
+		}
+		identity = identity || column.Identity
+		fmt.Fprintf(&body, "\t%s %s `glue:%s`\n", field, tp, strconv.Quote(tags[column.Name].String(field, column)))
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by glue-gen. DO NOT EDIT.\n\npackage %s\n\nimport (\n", pkg)
+	fmt.Fprintf(&buf, "\t\"github.com/yhyzgn/glue\"\n")
+	if identity {
+		fmt.Fprintf(&buf, "\t\"github.com/yhyzgn/glue/primary\"\n")
+	}
+	if hasTime {
+		fmt.Fprintf(&buf, "\t\"time\"\n")
+	}
+	fmt.Fprintf(&buf, ")\n\ntype %s struct {\n\tglue.TableModel\n%s}\n\n", name, body.String())
+	fmt.Fprintf(&buf, "func (*%s) TableName() string {\n\treturn %s\n}\n", name, strconv.Quote(info.Name))
+	if identity {
+		fmt.Fprintf(&buf, "\nfunc (*%s) PrimaryStrategy() glue.Strategy {\n\treturn &primary.AutoIncrement{}\n}\n", name)
+	}
+	return format.Source(buf.Bytes())
+}
+
+type columnTag struct {
+	primary bool
+	indexes []string
+	fks     []string
+}
+
+func columnTags(info *internal.TableInfo) map[string]*columnTag {
+	tags := make(map[string]*columnTag)
+	for _, column := range info.Columns {
+		tags[column.Name] = new(columnTag)
+	}
+	for _, name := range info.PrimaryKeys {
+		if tag, ok := tags[name]; ok {
+			tag.primary = true
+		}
+	}
+	// 同名索引和外键在多个字段上声明即为组合索引和组合外键
+	for _, index := range info.Indexes {
+		key := internal.TagIndex
+		switch index.Type {
+		case internal.IndexUnique:
+			key = internal.TagUnique
+		case internal.IndexFullText:
+			key = internal.TagFullText
+		case internal.IndexSpatial:
+			key = internal.TagSpatial
+		}
+		for _, column := range index.Columns {
+			if tag, ok := tags[column]; ok {
+				tag.indexes = append(tag.indexes, key+":"+index.Name)
+			}
+		}
+	}
+	for _, fk := range info.ForeignKeys {
+		for i, column := range fk.Columns {
+			if tag, ok := tags[column]; ok {
+				tag.fks = append(tag.fks, fmt.Sprintf("%s:%s,%s(%s)", internal.TagForeign, fk.Name, fk.Table, fk.References[i]))
+			}
+		}
+	}
+	return tags
+}
+
+// String 生成 glue 标签，列名与字段名的默认列名一致时省略 column
+func (t *columnTag) String(field string, column *internal.ColumnInfo) string {
+	items := make([]string, 0)
+	if internal.SnakeCase(field) != column.Name {
+		items = append(items, internal.TagColumn+":"+column.Name)
+	}
+	items = append(items, internal.TagType+":"+column.Type)
+	if t.primary {
+		items = append(items, internal.TagPrimary)
+	} else if !column.Nullable {
+		items = append(items, internal.TagNotNull)
+	}
+	if column.Default != nil && !column.Identity {
+		items = append(items, internal.TagDefault+":"+tagValue(*column.Default))
+	}
+	if column.Comment != "" {
+		items = append(items, internal.TagComment+":"+tagValue(column.Comment))
+	}
+	// 一个字段同类型的索引只能声明一个，其余的丢弃
+	seen := make(map[string]bool)
+	for _, index := range t.indexes {
+		key := index[:strings.Index(index, ":")]
+		if !seen[key] {
+			seen[key] = true
+			items = append(items, index)
+		}
+	}
+	if len(t.fks) > 0 {
+		items = append(items, t.fks[0])
+	}
+	return strings.Join(items, ";")
+}
+
+// tagValue 标签以 ; 分隔，结构体标签位于反引号中，需要替换这两个字符
+func tagValue(value string) string {
+	return strings.NewReplacer(";", ",", "`", "'", "\n", " ").Replace(strings.TrimSpace(value))
+}
+
+// goType 按列类型推导 Go 类型，无法识别的类型使用 string
+func goType(column *internal.ColumnInfo) string {
+	tp := strings.ToLower(strings.TrimSpace(column.Type))
+	unsigned := strings.Contains(tp, "unsigned")
+	base, args := tp, ""
+	if idx := strings.Index(tp, "("); idx >= 0 {
+		base, args = strings.TrimSpace(tp[:idx]), tp[idx+1:]
+		if end := strings.Index(args, ")"); end >= 0 {
+			args = args[:end]
+		}
+	}
+	base = strings.TrimSpace(strings.TrimSuffix(base, "unsigned"))
+	precision, scale := numberArgs(args)
+
+	switch base {
+	case "bool", "boolean", "bit":
+		return "bool"
+	case "tinyint":
+		if args == "1" {
+			return "bool"
+		}
+		return signed(unsigned, "8")
+	case "smallint", "int2", "smallserial":
+		return signed(unsigned, "16")
+	case "int", "int4", "mediumint", "serial":
+		return signed(unsigned, "32")
+	case "integer", "bigint", "int8", "bigserial":
+		return signed(unsigned, "64")
+	case "number", "decimal", "numeric", "dec", "money", "smallmoney":
+		if base == "number" && precision > 0 && scale == 0 {
+			if precision <= 9 {
+				return "int32"
+			}
+			return "int64"
+		}
+		return "float64"
+	case "real", "float", "float4", "float8", "double", "double precision", "binary_float", "binary_double":
+		return "float64"
+	case "date", "datetime", "datetime2", "smalldatetime", "datetimeoffset", "time", "timestamp", "timestamptz",
+		"timestamp without time zone", "timestamp with time zone":
+		return "time.Time"
+	case "blob", "tinyblob", "mediumblob", "longblob", "bytea", "binary", "varbinary", "raw", "long raw", "image":
+		return "[]byte"
+	}
+	if strings.HasPrefix(base, "timestamp") {
+		return "time.Time"
+	}
+	return "string"
+}
+
+func signed(unsigned bool, bits string) string {
+	if unsigned {
+		return "uint" + bits
+	}
+	return "int" + bits
+}
+
+func numberArgs(args string) (precision, scale int) {
+	parts := strings.Split(args, ",")
+	precision, _ = strconv.Atoi(strings.TrimSpace(parts[0]))
+	if len(parts) > 1 {
+		scale, _ = strconv.Atoi(strings.TrimSpace(parts[1]))
+	}
+	return
+}
+
+// fileName 由结构体名生成文件名，带 _gen 后缀，避免表名以 _test、_linux 等结尾时被当作测试文件或按平台忽略
+func fileName(model string) string {
+	return internal.SnakeCase(model) + "_gen.go"
+}
+
+// exportedName 下划线命名转为导出的驼峰命名，如 user_id -> UserID
+func exportedName(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var sb strings.Builder
+	for _, word := range words {
+		lower := strings.ToLower(word)
+		if initialisms[lower] {
+			sb.WriteString(strings.ToUpper(lower))
+			continue
+		}
+		// 全大写的单词（如 oracle 的列名）按小写处理
+		if strings.ToUpper(word) == word {
+			word = lower
+		}
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		sb.WriteString(string(runes))
+	}
+	result := sb.String()
+	if result == "" || !unicode.IsLetter([]rune(result)[0]) {
+		result = "X" + result
+	}
+	return result
+}
@@ -0,0 +1,122 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-02-03 16:00
+// version: 1.0.0
+// desc   : 
+
+package gen
+
+import (
+	"context"
+	"database/sql"
+	"github.com/yhyzgn/glue/dialect/sqlite"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	for _, ddl := range []string{
+		"CREATE TABLE team (id INTEGER PRIMARY KEY AUTOINCREMENT, name VARCHAR(64) NOT NULL)",
+		"CREATE TABLE user_account (id INTEGER PRIMARY KEY AUTOINCREMENT, team_id INTEGER NOT NULL REFERENCES team (id), " +
+			"nick_name VARCHAR(32), score REAL NOT NULL DEFAULT 0, avatar BLOB, created_at DATETIME NOT NULL)",
+		"CREATE UNIQUE INDEX uk_user_account_nick_name ON user_account (nick_name)",
+	} {
+		if _, err := db.Exec(ddl); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := Generate(context.Background(), sqlite.Dialect(), db, &Config{Package: "model", Tables: []string{"user_account"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].Name != "user_account_gen.go" {
+		t.Fatalf("unexpected files %v", files)
+	}
+	source := string(files[0].Source)
+	compact := strings.Join(strings.Fields(source), " ")
+	for _, expected := range []string{
+		"package model",
+		"type UserAccount struct {\n\tglue.TableModel\n",
+		"\tID        int64      `glue:\"type:INTEGER;pk\"`\n",
+		"\tTeamID    int64      `glue:\"type:INTEGER;notnull;fk:fk_user_account_team_id,team(id)\"`\n",
+		"\tNickName  *string    `glue:\"type:VARCHAR(32);unique:uk_user_account_nick_name\"`\n",
+		"\tScore     float64    `glue:\"type:REAL;notnull;default:0\"`\n",
+		"\tAvatar    []byte    `glue:\"type:BLOB\"`\n",
+		"\tCreatedAt time.Time  `glue:\"type:DATETIME;notnull\"`\n",
+		"func (*UserAccount) TableName() string {\n\treturn \"user_account\"\n}",
+		"func (*UserAccount) PrimaryStrategy() glue.Strategy {\n\treturn &primary.AutoIncrement{}\n}",
+	} {
+		if !strings.Contains(compact, strings.Join(strings.Fields(expected), " ")) {
+			t.Errorf("expect %q in generated source:\n%s", expected, source)
+		}
+	}
+}
+
+func TestExportedName(t *testing.T) {
+	cases := map[string]string{
+		"user_id":   "UserID",
+		"USER_NAME": "UserName",
+		"http_url":  "HTTPURL",
+		"2fa_code":  "X2faCode",
+		"createdAt": "CreatedAt",
+	}
+	for name, expected := range cases {
+		if actual := exportedName(name); actual != expected {
+			t.Errorf("exportedName(%q) = %q, want %q", name, actual, expected)
+		}
+	}
+}
+
+func TestFileName(t *testing.T) {
+	cases := map[string]string{
+		"user_test":       "user_test_gen.go",
+		"data_linux":      "data_linux_gen.go",
+		"x_windows_amd64": "x_windows_amd64_gen.go",
+		"_tmp":            "tmp_gen.go",
+		"USER_ACCOUNT":    "user_account_gen.go",
+	}
+	for table, expected := range cases {
+		if actual := fileName(exportedName(table)); actual != expected {
+			t.Errorf("fileName of %q = %q, want %q", table, actual, expected)
+		}
+	}
+}
+
+func TestGenerateCollision(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	for _, ddl := range []string{"CREATE TABLE user_info (id INTEGER)", "CREATE TABLE \"UserInfo\" (id INTEGER)"} {
+		if _, err := db.Exec(ddl); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := Generate(context.Background(), sqlite.Dialect(), db, nil); err == nil || !strings.Contains(err.Error(), "both generate model UserInfo") {
+		t.Errorf("colliding tables should be rejected: %v", err)
+	}
+}
@@ -19,3 +19,135 @@
 // desc   : 
 
 package glue
+
+import "github.com/yhyzgn/glue/internal"
+
+type (
+	Table      = internal.Table
+	TableModel = internal.TableModel
+	Strategy   = internal.Strategy
+	Definition = internal.Definition
+	Field      = internal.Field
+	Index      = internal.Index
+	ForeignKey = internal.ForeignKey
+	Command    = internal.Command
+	Dialect    = internal.Dialect
+	Executor   = internal.Executor
+
+	UnknownColumn  = internal.UnknownColumn
+	Condition      = internal.Condition
+	Key            = internal.Key
+	Cursor         = internal.Cursor
+	TableInfo      = internal.TableInfo
+	ColumnInfo     = internal.ColumnInfo
+	IndexInfo      = internal.IndexInfo
+	ForeignKeyInfo = internal.ForeignKeyInfo
+	ErrorKind      = internal.ErrorKind
+	Error          = internal.Error
+)
+
+const (
+	KindUnknown       = internal.KindUnknown
+	KindSerialization = internal.KindSerialization
+	KindDeadlock      = internal.KindDeadlock
+	KindLockTimeout   = internal.KindLockTimeout
+
+	KindUniqueViolation     = internal.KindUniqueViolation
+	KindForeignKeyViolation = internal.KindForeignKeyViolation
+	KindNotNullViolation    = internal.KindNotNullViolation
+	KindCheckViolation      = internal.KindCheckViolation
+	KindNoRows              = internal.KindNoRows
+	KindTableNotFound       = internal.KindTableNotFound
+)
+
+// 配合 errors.Is 判断错误分类，errors.As 到 *Error 可取得约束、表和列名
+var (
+	ErrUniqueViolation     = internal.ErrUniqueViolation
+	ErrForeignKeyViolation = internal.ErrForeignKeyViolation
+	ErrNotNullViolation    = internal.ErrNotNullViolation
+	ErrCheckViolation      = internal.ErrCheckViolation
+	ErrNoRows              = internal.ErrNoRows
+	ErrTableNotFound       = internal.ErrTableNotFound
+)
+
+// Parse 根据结构体的 glue 标签解析出表定义，未声明 type 标签的列类型由 dialect 推导
+func Parse(dialect Dialect, table Table) (*Definition, error) {
+	return internal.Parse(dialect, table)
+}
+
+func NewCursor(values ...interface{}) *Cursor {
+	return internal.NewCursor(values...)
+}
+
+func DecodeCursor(token string) (*Cursor, error) {
+	return internal.DecodeCursor(token)
+}
+
+func Eq(column string, value interface{}) Condition {
+	return internal.Eq(column, value)
+}
+
+func Ne(column string, value interface{}) Condition {
+	return internal.Ne(column, value)
+}
+
+func Gt(column string, value interface{}) Condition {
+	return internal.Gt(column, value)
+}
+
+func Gte(column string, value interface{}) Condition {
+	return internal.Gte(column, value)
+}
+
+func Lt(column string, value interface{}) Condition {
+	return internal.Lt(column, value)
+}
+
+func Lte(column string, value interface{}) Condition {
+	return internal.Lte(column, value)
+}
+
+func Like(column string, value interface{}) Condition {
+	return internal.Like(column, value)
+}
+
+func NotLike(column string, value interface{}) Condition {
+	return internal.NotLike(column, value)
+}
+
+func In(column string, values ...interface{}) Condition {
+	return internal.In(column, values...)
+}
+
+func NotIn(column string, values ...interface{}) Condition {
+	return internal.NotIn(column, values...)
+}
+
+func Between(column string, from, to interface{}) Condition {
+	return internal.Between(column, from, to)
+}
+
+func IsNull(column string) Condition {
+	return internal.IsNull(column)
+}
+
+func IsNotNull(column string) Condition {
+	return internal.IsNotNull(column)
+}
+
+func And(conditions ...Condition) Condition {
+	return internal.And(conditions...)
+}
+
+func Or(conditions ...Condition) Condition {
+	return internal.Or(conditions...)
+}
+
+func Not(condition Condition) Condition {
+	return internal.Not(condition)
+}
+
+// Raw 原生条件片段，使用 ? 作为占位符
+func Raw(sql string, args ...interface{}) Condition {
+	return internal.Raw(sql, args...)
+}
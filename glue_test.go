@@ -21,9 +21,21 @@
 package glue
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"github.com/yhyzgn/glue/dialect"
+	"github.com/yhyzgn/glue/dialect/clickhouse"
+	"github.com/yhyzgn/glue/dialect/cockroach"
+	"github.com/yhyzgn/glue/dialect/mariadb"
 	"github.com/yhyzgn/glue/dialect/mssql"
+	"github.com/yhyzgn/glue/dialect/mysql"
+	"github.com/yhyzgn/glue/dialect/postgres"
+	"github.com/yhyzgn/glue/dialect/sqlite"
+	"github.com/yhyzgn/glue/dialect/tidb"
+	"github.com/yhyzgn/glue/internal"
+	"github.com/yhyzgn/glue/primary"
+	"strings"
 	"testing"
 )
 
@@ -31,9 +43,440 @@ func Test(t *testing.T) {
 	dl := mssql.Dialect()
 
 	fmt.Println(dl.Name())
-	fmt.Println(dialect.Current.Name())
 
-	cmd := dl.HasTable("user")
+	cmd := dl.HasTable("dbo", "user")
 	fmt.Println(cmd.SQL())
 	fmt.Println(cmd.Args()...)
 }
+
+func TestRegistry(t *testing.T) {
+	if dl, ok := dialect.Lookup("postgres"); !ok || dl.Name() != "postgres" {
+		t.Errorf("unexpected dialect %v", dl)
+	}
+	if dl, ok := dialect.Lookup("sqlite3"); !ok || dl.Name() != "sqlite" {
+		t.Errorf("unexpected dialect %v of driver sqlite3", dl)
+	}
+	if _, ok := dialect.Lookup("unknown"); ok {
+		t.Error("unknown dialect should not be found")
+	}
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	dl, err := dialect.FromDB(db)
+	if err != nil || dl.Name() != "sqlite" {
+		t.Errorf("unexpected dialect %v from db: %v", dl, err)
+	}
+
+	// 构造方言不会修改默认方言
+	mysql.Dialect()
+	postgres.Dialect()
+	if dialect.Current != nil {
+		t.Errorf("default dialect should not be set, got %s", dialect.Current.Name())
+	}
+}
+
+func TestPage(t *testing.T) {
+	cases := []struct {
+		dialect Dialect
+		cmd     *internal.Command
+		sql     string
+		args    string
+	}{
+		{mysql.Dialect(), internal.NewCommand("SELECT * FROM t ORDER BY id"), "SELECT * FROM t ORDER BY id\nLIMIT ?\nOFFSET ?", "[10 20]"},
+		{postgres.Dialect(), internal.NewCommand("SELECT * FROM t WHERE a = $1"), "SELECT * FROM t WHERE a = $1\nLIMIT $2\nOFFSET $3", "[a 10 20]"},
+		{sqlite.Dialect(), internal.NewCommand("SELECT * FROM t"), "SELECT * FROM t\nLIMIT ?\nOFFSET ?", "[10 20]"},
+		{mssql.Dialect(), internal.NewCommand("SELECT * FROM t"), "SELECT * FROM t\nORDER BY (SELECT NULL)\nOFFSET @p1 ROWS\nFETCH NEXT @p2 ROWS ONLY", "[20 10]"},
+		{mssql.Dialect(), internal.NewCommand("SELECT * FROM t ORDER BY id"), "SELECT * FROM t ORDER BY id\nOFFSET @p1 ROWS\nFETCH NEXT @p2 ROWS ONLY", "[20 10]"},
+	}
+	for _, cs := range cases {
+		if cs.dialect.Name() == "postgres" {
+			cs.cmd.Arguments("a")
+		}
+		cmd := cs.dialect.Page(cs.cmd, 3, 10)
+		if cmd.SQL() != cs.sql {
+			t.Errorf("unexpected %s page sql: %s", cs.dialect.Name(), cmd.SQL())
+		}
+		if args := fmt.Sprint(cmd.Args()); args != cs.args {
+			t.Errorf("unexpected %s page args: %s", cs.dialect.Name(), args)
+		}
+	}
+
+	// 页码小于 1 时按第 1 页处理
+	cmd := postgres.Dialect().Page(internal.NewCommand("SELECT * FROM t"), 0, 10)
+	if cmd.SQL() != "SELECT * FROM t\nLIMIT $1" || len(cmd.Args()) != 1 {
+		t.Errorf("unexpected first page: %s %v", cmd.SQL(), cmd.Args())
+	}
+}
+
+func TestSeek(t *testing.T) {
+	token, err := NewCursor(int64(18), "glue", int64(100)).Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cursor, err := DecodeCursor(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd := internal.NewCommand("SELECT * FROM audit WHERE kind = $1").Arguments("login")
+
+	keys := []*Key{{Column: "age"}, {Column: "name"}, {Column: "id"}}
+	seek, err := postgres.Dialect().Seek(cmd, keys, cursor, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT * FROM (\nSELECT * FROM audit WHERE kind = $1\n) T\nWHERE\n\t(T.\"age\", T.\"name\", T.\"id\") > ($2, $3, $4)\nORDER BY\n\tT.\"age\", T.\"name\", T.\"id\"\nLIMIT $5"
+	if seek.SQL() != expected {
+		t.Errorf("unexpected seek sql: %s", seek.SQL())
+	}
+	if fmt.Sprint(seek.Args()) != "[login 18 glue 100 20]" {
+		t.Errorf("unexpected seek args: %v", seek.Args())
+	}
+
+	keys = []*Key{{Column: "created_at", Desc: true}, {Column: "id"}}
+	seek, err = mssql.Dialect().Seek(internal.NewCommand("SELECT * FROM audit"), keys, NewCursor("2020-01-01", 7), 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "SELECT * FROM (\nSELECT * FROM audit\n) T\nWHERE\n\t(T.[created_at] < @p1) OR (T.[created_at] = @p2 AND T.[id] > @p3)\nORDER BY\n\tT.[created_at] DESC, T.[id]\nOFFSET @p4 ROWS\nFETCH NEXT @p5 ROWS ONLY"
+	if seek.SQL() != expected {
+		t.Errorf("unexpected seek sql: %s", seek.SQL())
+	}
+	if fmt.Sprint(seek.Args()) != "[2020-01-01 2020-01-01 7 0 20]" {
+		t.Errorf("unexpected seek args: %v", seek.Args())
+	}
+
+	// 列表查询通常自带 ORDER BY，mssql 的子查询只有带 TOP 或 OFFSET 时才允许
+	if _, err = mssql.Dialect().Seek(internal.NewCommand("SELECT * FROM audit ORDER BY id"), keys, nil, 20); err == nil {
+		t.Error("mssql seek query with ORDER BY should be rejected")
+	}
+	if _, err = mssql.Dialect().Seek(internal.NewCommand("SELECT TOP 100 * FROM audit ORDER BY id"), keys, nil, 20); err != nil {
+		t.Error(err)
+	}
+
+	// keys 的列必须出现在查询列中
+	db := openSQLite(t)
+	defer db.Close()
+	if _, err := db.Exec("INSERT INTO account (name, age) VALUES ('glue', 18), ('yhyzgn', 20)"); err != nil {
+		t.Fatal(err)
+	}
+	byID := []*Key{{Column: "id"}}
+	seek, _ = sqlite.Dialect().Seek(internal.NewCommand("SELECT id, name FROM account ORDER BY name"), byID, NewCursor(int64(1)), 20)
+	var name string
+	if err := db.QueryRow(seek.SQL(), seek.Args()...).Scan(new(int64), &name); err != nil || name != "yhyzgn" {
+		t.Errorf("unexpected seek result %q: %v", name, err)
+	}
+	seek, _ = sqlite.Dialect().Seek(internal.NewCommand("SELECT name FROM account"), byID, NewCursor(int64(1)), 20)
+	if err := db.QueryRow(seek.SQL(), seek.Args()...).Scan(&name); err == nil {
+		t.Error("seek keys missing from the select list should fail")
+	}
+
+	// 值个数不一致的游标不能退回第一页
+	if seek, err = postgres.Dialect().Seek(cmd, keys, NewCursor(int64(18)), 20); err == nil || seek != nil {
+		t.Errorf("mismatched cursor should be rejected: %v", seek)
+	}
+}
+
+func TestInsertReturning(t *testing.T) {
+	value := &internal.ExecValue{Table: "user", Columns: []string{"name"}, Values: []interface{}{"glue"}, Primary: "id"}
+
+	cmd := postgres.Dialect().Insert(value)
+	if cmd.SQL() != `INSERT INTO "user" ("name") VALUES ($1) RETURNING "id"` || cmd.GeneratedKey() != "id" {
+		t.Errorf("unexpected postgres insert: %s", cmd.SQL())
+	}
+	cmd = mssql.Dialect().Insert(value)
+	if cmd.SQL() != "INSERT INTO [user] ([name]) OUTPUT INSERTED.[id] VALUES (@p1)" || cmd.GeneratedKey() != "id" {
+		t.Errorf("unexpected mssql insert: %s", cmd.SQL())
+	}
+	cmd = mysql.Dialect().Insert(value)
+	if cmd.SQL() != "INSERT INTO `user` (`name`) VALUES (?)" || cmd.GeneratedKey() != "" {
+		t.Errorf("unexpected mysql insert: %s", cmd.SQL())
+	}
+}
+
+type member struct {
+	TableModel
+	ID      int64  `glue:"pk;comment:主键"`
+	Code    string `glue:"size:32;notnull;unique"`
+	Name    string `glue:"size:64;index:idx_member_name"`
+	Age     int    `glue:"notnull;default:0;index:idx_member_name"`
+	GroupID int64  `glue:"fk:groups(id)"`
+}
+
+func (*member) TableName() string {
+	return "member"
+}
+
+func (*member) PrimaryStrategy() Strategy {
+	return &primary.AutoIncrement{}
+}
+
+func TestCreateTable(t *testing.T) {
+	cases := []struct {
+		dialect  Dialect
+		identity string
+		sqls     []string
+	}{
+		{mysql.Dialect(), "`id` BIGINT NOT NULL AUTO_INCREMENT COMMENT '主键'", []string{
+			"CREATE INDEX `idx_member_name` ON `member` (`name`, `age`)",
+			"CREATE UNIQUE INDEX `uk_member_code` ON `member` (`code`)",
+		}},
+		{postgres.Dialect(), `"id" BIGINT GENERATED BY DEFAULT AS IDENTITY`, []string{
+			`CREATE INDEX "idx_member_name" ON "member" ("name", "age")`,
+			`CREATE UNIQUE INDEX "uk_member_code" ON "member" ("code")`,
+			`COMMENT ON COLUMN "member"."id" IS '主键'`,
+		}},
+		{sqlite.Dialect(), `"id" INTEGER PRIMARY KEY AUTOINCREMENT`, []string{
+			`CREATE INDEX "idx_member_name" ON "member" ("name", "age")`,
+			`CREATE UNIQUE INDEX "uk_member_code" ON "member" ("code")`,
+		}},
+		{mssql.Dialect(), "[id] BIGINT IDENTITY(1,1) NOT NULL", []string{
+			"CREATE INDEX [idx_member_name] ON [member] ([name], [age])",
+			"CREATE UNIQUE INDEX [uk_member_code] ON [member] ([code])",
+			"DECLARE @schema SYSNAME = SCHEMA_NAME();\nEXEC sp_addextendedproperty\n\t@name = N'MS_Description', @value = N'主键',\n\t@level0type = N'SCHEMA', @level0name = @schema,\n\t@level1type = N'TABLE', @level1name = N'member',\n\t@level2type = N'COLUMN', @level2name = N'id'",
+		}},
+	}
+	for _, cs := range cases {
+		def, err := Parse(cs.dialect, &member{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		commands := cs.dialect.CreateTable(def)
+		if len(commands) != len(cs.sqls)+1 {
+			t.Fatalf("unexpected %s commands count %d", cs.dialect.Name(), len(commands))
+		}
+		if !strings.Contains(commands[0].SQL(), "\n\t"+cs.identity+",") {
+			t.Errorf("unexpected %s identity column:\n%s", cs.dialect.Name(), commands[0].SQL())
+		}
+		if primaryKey := strings.Contains(commands[0].SQL(), "PRIMARY KEY ("); primaryKey == (cs.dialect.Name() == "sqlite") {
+			t.Errorf("unexpected %s primary key:\n%s", cs.dialect.Name(), commands[0].SQL())
+		}
+		for i, sql := range cs.sqls {
+			if commands[i+1].SQL() != sql {
+				t.Errorf("unexpected %s command:\n%s\nexpected:\n%s", cs.dialect.Name(), commands[i+1].SQL(), sql)
+			}
+		}
+	}
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	def, _ := Parse(sqlite.Dialect(), &member{})
+	for _, cmd := range sqlite.Dialect().CreateTable(def) {
+		if _, err := db.Exec(cmd.SQL(), cmd.Args()...); err != nil {
+			t.Fatalf("%v\n%s", err, cmd.SQL())
+		}
+	}
+}
+
+// uuidDefault 在 postgres 中由列默认值生成主键
+type uuidDefault struct{}
+
+func (*uuidDefault) Primary() interface{} {
+	return nil
+}
+
+func (*uuidDefault) Declare(dialect internal.Dialect) *internal.Declaration {
+	if dialect.Name() != "postgres" {
+		return nil
+	}
+	return &internal.Declaration{Default: "gen_random_uuid()"}
+}
+
+func TestDeclareStrategy(t *testing.T) {
+	def, err := Parse(postgres.Dialect(), &token{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	def.Strategy = &uuidDefault{}
+	sql := postgres.Dialect().CreateTable(def)[0].SQL()
+	if !strings.Contains(sql, `"id" VARCHAR(255) NOT NULL DEFAULT gen_random_uuid(),`) || strings.Contains(sql, "IDENTITY") {
+		t.Errorf("unexpected postgres create table:\n%s", sql)
+	}
+	def, _ = Parse(mysql.Dialect(), &token{})
+	def.Strategy = &uuidDefault{}
+	if sql := mysql.Dialect().CreateTable(def)[0].SQL(); strings.Contains(sql, "DEFAULT") || strings.Contains(sql, "AUTO_INCREMENT") {
+		t.Errorf("unexpected mysql create table:\n%s", sql)
+	}
+
+	sequence := &primary.Sequence{Name: "token_seq"}
+	def.Strategy = sequence
+	commands := postgres.Dialect().CreateTable(def)
+	if len(commands) != 2 || commands[0].SQL() != `CREATE SEQUENCE "token_seq" START WITH 1 INCREMENT BY 1` || !strings.HasPrefix(commands[1].SQL(), `CREATE TABLE "token"`) {
+		t.Errorf("unexpected postgres sequence commands: %v", commands)
+	}
+	if commands := sqlite.Dialect().CreateTable(def); len(commands) != 1 {
+		t.Errorf("sqlite should ignore sequences: %v", commands)
+	}
+	if dialect.IdentityOf(postgres.Dialect(), def) != nil {
+		t.Error("sequence should not be an identity")
+	}
+}
+
+// counter 使用 int32 自增主键
+type counter struct {
+	TableModel
+	ID int32 `glue:"pk"`
+}
+
+func (*counter) PrimaryStrategy() Strategy {
+	return &primary.AutoIncrement{}
+}
+
+func TestCompatibleDialects(t *testing.T) {
+	for _, name := range []string{"mariadb", "tidb", "cockroach", "clickhouse"} {
+		if dl, ok := dialect.Lookup(name); !ok || dl.Name() != name {
+			t.Errorf("unexpected dialect %v of %s", dl, name)
+		}
+	}
+	// 兼容方言与原方言共用驱动名，按驱动名查找时仍返回原方言
+	if dl, _ := dialect.Lookup("mysql"); dl.Name() != "mysql" {
+		t.Errorf("unexpected dialect %s of driver mysql", dl.Name())
+	}
+
+	def, err := Parse(tidb.Dialect(), &member{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	commands := tidb.Dialect().CreateTable(def)
+	if sql := commands[0].SQL(); !strings.Contains(sql, "`id` BIGINT NOT NULL AUTO_RANDOM") || strings.Contains(sql, "FOREIGN KEY") {
+		t.Errorf("unexpected tidb create table:\n%s", sql)
+	}
+
+	def, _ = Parse(cockroach.Dialect(), &member{})
+	commands = cockroach.Dialect().CreateTable(def)
+	if sql := commands[0].SQL(); !strings.Contains(sql, `"id" INT8 NOT NULL DEFAULT unique_rowid()`) {
+		t.Errorf("unexpected cockroach create table:\n%s", sql)
+	}
+	if _, err = Parse(cockroach.Dialect(), &counter{}); err == nil {
+		t.Error("cockroach should reject int32 identity")
+	}
+	if _, err = Parse(postgres.Dialect(), &counter{}); err != nil {
+		t.Error(err)
+	}
+
+	value := &internal.ExecValue{Table: "user", Columns: []string{"name"}, Values: []interface{}{"glue"}, Primary: "id"}
+	if cmd := mariadb.Dialect().Insert(value); cmd.SQL() != "INSERT INTO `user` (`name`) VALUES (?) RETURNING `id`" {
+		t.Errorf("unexpected mariadb insert: %s", cmd.SQL())
+	}
+	if next := mariadb.Dialect().NextValue("seq_user"); next != "NEXTVAL(`seq_user`)" {
+		t.Errorf("unexpected mariadb next value: %s", next)
+	}
+
+	ch := clickhouse.Dialect()
+	def, _ = Parse(ch, &member{})
+	commands = ch.CreateTable(def)
+	if len(commands) != 1 {
+		t.Fatalf("unexpected clickhouse commands count %d", len(commands))
+	}
+	if sql := commands[0].SQL(); !strings.Contains(sql, "`name` Nullable(String)") || !strings.HasSuffix(sql, "ENGINE = MergeTree()\nORDER BY (`id`)") {
+		t.Errorf("unexpected clickhouse create table:\n%s", sql)
+	}
+	update := &internal.ExecValue{Table: "user", Columns: []string{"name"}, Values: []interface{}{"glue"}, Where: Eq("id", 1)}
+	if cmd := ch.Update(update); cmd.SQL() != "ALTER TABLE `user` UPDATE `name` = ? WHERE `id` = ?" || len(cmd.Args()) != 2 {
+		t.Errorf("unexpected clickhouse update: %s %v", cmd.SQL(), cmd.Args())
+	}
+	if cmd := ch.Delete(&internal.ExecValue{Table: "user"}); cmd.SQL() != "ALTER TABLE `user` DELETE WHERE 1" {
+		t.Errorf("unexpected clickhouse delete: %s", cmd.SQL())
+	}
+	if transactional, ok := Dialect(ch).(dialect.Transactional); !ok || transactional.Transactional() {
+		t.Error("clickhouse should not be transactional")
+	}
+}
+
+func TestExists(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	dl := sqlite.Dialect()
+	def, _ := Parse(dl, &member{})
+	for _, cmd := range dl.CreateTable(def) {
+		if _, err := db.Exec(cmd.SQL(), cmd.Args()...); err != nil {
+			t.Fatalf("%v\n%s", err, cmd.SQL())
+		}
+	}
+
+	ctx := context.Background()
+	if schema, err := dl.Schema(ctx, db); err != nil || schema != "main" {
+		t.Fatalf("unexpected schema %q: %v", schema, err)
+	}
+	checks := []struct {
+		name     string
+		check    func() (bool, error)
+		expected bool
+	}{
+		{"table", func() (bool, error) { return HasTable(ctx, dl, db, "member") }, true},
+		{"missing table", func() (bool, error) { return HasTable(ctx, dl, db, "groups") }, false},
+		{"column", func() (bool, error) { return HasColumn(ctx, dl, db, "member", "code") }, true},
+		{"missing column", func() (bool, error) { return HasColumn(ctx, dl, db, "member", "email") }, false},
+		{"index", func() (bool, error) { return HasIndex(ctx, dl, db, "member", "idx_member_name") }, true},
+		{"foreign key", func() (bool, error) { return HasForeignKey(ctx, dl, db, "member", "fk_member_group_id") }, true},
+		{"missing foreign key", func() (bool, error) { return HasForeignKey(ctx, dl, db, "member", "fk_member_code") }, false},
+	}
+	for _, check := range checks {
+		if exists, err := check.check(); err != nil || exists != check.expected {
+			t.Errorf("unexpected %s existence %v: %v", check.name, exists, err)
+		}
+	}
+
+	// schema 按解析结果绑定，postgres 的表名参数折叠为小写
+	cmd := postgres.Dialect().HasTable("public", "Member")
+	if fmt.Sprint(cmd.Args()) != "[public member]" {
+		t.Errorf("unexpected postgres args: %v", cmd.Args())
+	}
+}
+
+func TestAlterColumn(t *testing.T) {
+	sqls := func(commands []*Command) string {
+		result := make([]string, len(commands))
+		for i, cmd := range commands {
+			result[i] = cmd.SQL()
+		}
+		return strings.Join(result, ";\n")
+	}
+	cases := []struct {
+		dialect Dialect
+		add     string
+		modify  string
+	}{
+		{
+			mysql.Dialect(),
+			"ALTER TABLE `user` ADD COLUMN `age` INT DEFAULT 0 NOT NULL COMMENT 'it''s the age'",
+			"ALTER TABLE `user` CHANGE COLUMN `age` `age` INT DEFAULT 0 NOT NULL COMMENT 'it''s the age'",
+		},
+		{
+			postgres.Dialect(),
+			`ALTER TABLE "user" ADD COLUMN "age" INT DEFAULT 0 NOT NULL;` + "\n" + `COMMENT ON COLUMN "user"."age" IS 'it''s the age'`,
+			`ALTER TABLE "user" ALTER COLUMN "age" TYPE INT, ALTER COLUMN "age" SET NOT NULL, ALTER COLUMN "age" SET DEFAULT 0;` + "\n" + `COMMENT ON COLUMN "user"."age" IS 'it''s the age'`,
+		},
+		{
+			sqlite.Dialect(),
+			`ALTER TABLE "user" ADD COLUMN "age" INT DEFAULT 0 NOT NULL`,
+			"",
+		},
+	}
+	for _, cs := range cases {
+		if sql := sqls(cs.dialect.AddColumn("user", "age", "INT", "it's the age", true, 0)); sql != cs.add {
+			t.Errorf("unexpected %s add column:\n%s", cs.dialect.Name(), sql)
+		}
+		if sql := sqls(cs.dialect.ModifyColumn("user", "age", "age", "INT", "it's the age", true, 0)); sql != cs.modify {
+			t.Errorf("unexpected %s modify column:\n%s", cs.dialect.Name(), sql)
+		}
+	}
+
+	ms := mssql.Dialect()
+	if commands := ms.AddColumn("user", "age", "INT", "it's the age", false, nil); len(commands) != 2 || commands[0].SQL() != "ALTER TABLE [user] ADD [age] INT NULL" || !strings.Contains(commands[1].SQL(), "sp_addextendedproperty") || !strings.Contains(commands[1].SQL(), "N'it''s the age'") {
+		t.Errorf("unexpected mssql add column:\n%s", sqls(commands))
+	}
+	commands := ms.ModifyColumn("user", "age", "years", "BIGINT", "it's the age", true, 0)
+	if len(commands) != 3 || commands[0].SQL() != "EXEC sp_rename N'user.age', N'years', 'COLUMN'" || commands[1].SQL() != "ALTER TABLE [user] ALTER COLUMN [years] BIGINT NOT NULL" || !strings.Contains(commands[2].SQL(), "sp_updateextendedproperty") {
+		t.Errorf("unexpected mssql modify column:\n%s", sqls(commands))
+	}
+}
@@ -3,6 +3,7 @@ module github.com/yhyzgn/glue
 go 1.13
 
 require (
+	github.com/ClickHouse/clickhouse-go v1.4.5
 	github.com/denisenkom/go-mssqldb v0.0.0-20191128021309-1d7a30a10f73
 	github.com/go-sql-driver/mysql v1.5.0
 	github.com/lib/pq v1.3.0
@@ -21,8 +21,10 @@
 package internal
 
 type Command struct {
-	sql  string
-	args []interface{}
+	sql       string
+	args      []interface{}
+	generated string
+	into      interface{}
 }
 
 func NewCommand(sql string) *Command {
@@ -71,6 +73,22 @@ func (c *Command) Arguments(args ...interface{}) *Command {
 	return c
 }
 
+// Generated 标记该命令执行后会返回 column 列由数据库生成的值
+func (c *Command) Generated(column string) *Command {
+	c.generated = column
+	return c
+}
+
+// Into 指定取回的生成值写入的目标，dest 须为指针，未指定时按 int64 取回
+func (c *Command) Into(dest interface{}) *Command {
+	c.into = dest
+	return c
+}
+
+func (c *Command) Clone() *Command {
+	return NewCommand(c.sql).Arguments(c.args...).Generated(c.generated).Into(c.into)
+}
+
 func (c *Command) SQL() string {
 	return c.sql
 }
@@ -79,6 +97,14 @@ func (c *Command) Args() []interface{} {
 	return c.args
 }
 
+func (c *Command) GeneratedKey() string {
+	return c.generated
+}
+
+func (c *Command) Destination() interface{} {
+	return c.into
+}
+
 func (c *Command) tabs(sql string, tabs int) string {
 	for i := 0; i < tabs; i++ {
 		sql = "\t" + sql
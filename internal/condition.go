@@ -0,0 +1,254 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-01-20 10:18
+// version: 1.0.0
+// desc   : 
+
+package internal
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Condition 查询条件，Build 时占位符从 start 开始编号
+type Condition interface {
+	Build(driver Driver, start int) (string, []interface{})
+}
+
+type compare struct {
+	column   string
+	operator string
+	value    interface{}
+}
+
+type in struct {
+	column string
+	values []interface{}
+	not    bool
+}
+
+type between struct {
+	column string
+	from   interface{}
+	to     interface{}
+}
+
+type null struct {
+	column string
+	not    bool
+}
+
+type group struct {
+	operator   string
+	conditions []Condition
+}
+
+type not struct {
+	condition Condition
+}
+
+type raw struct {
+	sql  string
+	args []interface{}
+}
+
+func Eq(column string, value interface{}) Condition {
+	return &compare{column: column, operator: "=", value: value}
+}
+
+func Ne(column string, value interface{}) Condition {
+	return &compare{column: column, operator: "<>", value: value}
+}
+
+func Gt(column string, value interface{}) Condition {
+	return &compare{column: column, operator: ">", value: value}
+}
+
+func Gte(column string, value interface{}) Condition {
+	return &compare{column: column, operator: ">=", value: value}
+}
+
+func Lt(column string, value interface{}) Condition {
+	return &compare{column: column, operator: "<", value: value}
+}
+
+func Lte(column string, value interface{}) Condition {
+	return &compare{column: column, operator: "<=", value: value}
+}
+
+func Like(column string, value interface{}) Condition {
+	return &compare{column: column, operator: "LIKE", value: value}
+}
+
+func NotLike(column string, value interface{}) Condition {
+	return &compare{column: column, operator: "NOT LIKE", value: value}
+}
+
+// In 单个切片参数会被展开，In("id", []int{1, 2}) 与 In("id", 1, 2) 等价
+func In(column string, values ...interface{}) Condition {
+	return &in{column: column, values: flatten(values)}
+}
+
+func NotIn(column string, values ...interface{}) Condition {
+	return &in{column: column, values: flatten(values), not: true}
+}
+
+func Between(column string, from, to interface{}) Condition {
+	return &between{column: column, from: from, to: to}
+}
+
+func IsNull(column string) Condition {
+	return &null{column: column}
+}
+
+func IsNotNull(column string) Condition {
+	return &null{column: column, not: true}
+}
+
+func And(conditions ...Condition) Condition {
+	return &group{operator: "AND", conditions: conditions}
+}
+
+func Or(conditions ...Condition) Condition {
+	return &group{operator: "OR", conditions: conditions}
+}
+
+func Not(condition Condition) Condition {
+	return &not{condition: condition}
+}
+
+// Raw 原生条件片段，使用 ? 作为占位符，渲染时按方言重新编号
+func Raw(sql string, args ...interface{}) Condition {
+	return &raw{sql: sql, args: args}
+}
+
+func (c *compare) Build(driver Driver, start int) (string, []interface{}) {
+	return fmt.Sprintf("%s %s %s", driver.Quote(c.column), c.operator, driver.Placeholder(start)), []interface{}{c.value}
+}
+
+func (c *in) Build(driver Driver, start int) (string, []interface{}) {
+	if len(c.values) == 0 {
+		// 空集合：IN 恒假，NOT IN 恒真
+		if c.not {
+			return "1 = 1", nil
+		}
+		return "1 = 0", nil
+	}
+	holders := make([]string, len(c.values))
+	for i := range c.values {
+		holders[i] = driver.Placeholder(start + i)
+	}
+	operator := "IN"
+	if c.not {
+		operator = "NOT IN"
+	}
+	return fmt.Sprintf("%s %s (%s)", driver.Quote(c.column), operator, strings.Join(holders, ", ")), c.values
+}
+
+func (c *between) Build(driver Driver, start int) (string, []interface{}) {
+	return fmt.Sprintf("%s BETWEEN %s AND %s", driver.Quote(c.column), driver.Placeholder(start), driver.Placeholder(start+1)), []interface{}{c.from, c.to}
+}
+
+func (c *null) Build(driver Driver, start int) (string, []interface{}) {
+	if c.not {
+		return fmt.Sprintf("%s IS NOT NULL", driver.Quote(c.column)), nil
+	}
+	return fmt.Sprintf("%s IS NULL", driver.Quote(c.column)), nil
+}
+
+func (c *group) Build(driver Driver, start int) (string, []interface{}) {
+	conditions := make([]Condition, 0, len(c.conditions))
+	for _, condition := range c.conditions {
+		if condition != nil {
+			conditions = append(conditions, condition)
+		}
+	}
+	if len(conditions) == 1 {
+		return conditions[0].Build(driver, start)
+	}
+	parts := make([]string, 0, len(conditions))
+	args := make([]interface{}, 0)
+	for _, condition := range conditions {
+		sql, values := condition.Build(driver, start+len(args))
+		if sql == "" {
+			continue
+		}
+		// 组合条件和原生片段可能包含 OR，需要加括号保持优先级
+		switch condition.(type) {
+		case *group, *raw:
+			sql = "(" + sql + ")"
+		}
+		parts = append(parts, sql)
+		args = append(args, values...)
+	}
+	return strings.Join(parts, " "+c.operator+" "), args
+}
+
+func (c *not) Build(driver Driver, start int) (string, []interface{}) {
+	if c.condition == nil {
+		return "", nil
+	}
+	sql, args := c.condition.Build(driver, start)
+	if sql == "" {
+		return "", nil
+	}
+	return fmt.Sprintf("NOT (%s)", sql), args
+}
+
+func (c *raw) Build(driver Driver, start int) (string, []interface{}) {
+	return Renumber(c.sql, driver, start), c.args
+}
+
+// Renumber 将 sql 中引号之外的 ? 按方言占位符重新编号，序号从 start 开始
+func Renumber(sql string, driver Driver, start int) string {
+	var sb strings.Builder
+	var quote rune
+	index := start
+	for _, r := range sql {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '?':
+			sb.WriteString(driver.Placeholder(index))
+			index++
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+func flatten(values []interface{}) []interface{} {
+	if len(values) != 1 || values[0] == nil {
+		return values
+	}
+	value := reflect.ValueOf(values[0])
+	if (value.Kind() != reflect.Slice && value.Kind() != reflect.Array) || value.Type().Elem().Kind() == reflect.Uint8 {
+		return values
+	}
+	result := make([]interface{}, value.Len())
+	for i := 0; i < value.Len(); i++ {
+		result[i] = value.Index(i).Interface()
+	}
+	return result
+}
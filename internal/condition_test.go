@@ -0,0 +1,69 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-01-20 14:02
+// version: 1.0.0
+// desc   : 
+
+package internal
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type numbered struct{}
+
+func (*numbered) Name() string {
+	return "numbered"
+}
+
+func (*numbered) Driver() string {
+	return "numbered"
+}
+
+func (*numbered) Quote(key string) string {
+	return fmt.Sprintf(`"%s"`, key)
+}
+
+func (*numbered) Placeholder(index int) string {
+	return fmt.Sprintf("$%d", index)
+}
+
+func (*numbered) Database() string {
+	return ""
+}
+
+func TestCondition_Build(t *testing.T) {
+	cond := And(
+		Eq("name", "glue"),
+		Or(Gt("age", 18), IsNull("age")),
+		In("role", []int{1, 2}),
+		NotIn("status"),
+		Not(Between("score", 60, 80)),
+		Raw("code = ? OR code LIKE '%?'", "a"),
+	)
+	sql, args := cond.Build(new(numbered), 3)
+
+	expected := `"name" = $3 AND ("age" > $4 OR "age" IS NULL) AND "role" IN ($5, $6) AND 1 = 1 AND NOT ("score" BETWEEN $7 AND $8) AND (code = $9 OR code LIKE '%?')`
+	if sql != expected {
+		t.Fatalf("unexpected sql: %s", sql)
+	}
+	if !reflect.DeepEqual(args, []interface{}{"glue", 18, 1, 2, 60, 80, "a"}) {
+		t.Fatalf("unexpected args: %v", args)
+	}
+}
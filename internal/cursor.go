@@ -0,0 +1,73 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-01-22 10:30
+// version: 1.0.0
+// desc   : 
+
+package internal
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/gob"
+	"errors"
+	"time"
+)
+
+func init() {
+	gob.Register(time.Time{})
+	gob.Register([]byte{})
+}
+
+// Key 游标分页的排序键，所有键组合起来必须唯一，通常以主键结尾
+type Key struct {
+	Column string
+	Desc   bool
+}
+
+// Cursor 游标分页的位置，记录上一页最后一行各排序键的值
+type Cursor struct {
+	Values []interface{}
+}
+
+func NewCursor(values ...interface{}) *Cursor {
+	return &Cursor{Values: values}
+}
+
+// Encode 编码为可在 URL 中传递的不透明字符串
+func (c *Cursor) Encode() (string, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(c.Values); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+func DecodeCursor(token string) (*Cursor, error) {
+	if token == "" {
+		return nil, errors.New("glue: empty cursor")
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errors.New("glue: malformed cursor")
+	}
+	values := make([]interface{}, 0)
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&values); err != nil {
+		return nil, errors.New("glue: malformed cursor")
+	}
+	return &Cursor{Values: values}, nil
+}
@@ -40,15 +40,16 @@ type Model struct {
 }
 
 type Field struct {
-	Name        string
-	Type        reflect.Type
-	ElmType     reflect.Type
-	Column      string
-	SQLType     string
-	IsPrimary   bool
-	NotNull     bool
-	Default     interface{}
-	Comment     string
+	Name       string
+	Type       reflect.Type
+	ElmType    reflect.Type
+	FieldIndex []int
+	Column     string
+	SQLType    string
+	IsPrimary  bool
+	NotNull    bool
+	Default    interface{}
+	Comment    string
 }
 
 type Index struct {
@@ -21,6 +21,7 @@
 package internal
 
 import (
+	"context"
 	"database/sql"
 	"reflect"
 )
@@ -28,31 +29,40 @@ import (
 type Dialect interface {
 	Driver
 
-	InsertExecutor(executor Executor, command *Command) (sql.Result, error)
+	InsertExecutor(ctx context.Context, executor Executor, command *Command) (sql.Result, error)
 
-	UpdateExecutor(executor Executor, command *Command) (sql.Result, error)
+	UpdateExecutor(ctx context.Context, executor Executor, command *Command) (sql.Result, error)
 
 	SQLType(field *reflect.StructField) string
 
-	HasTable(name string) *Command
+	// Schema 执行 Database 查询得到当前 schema，结果由方言实例缓存
+	Schema(ctx context.Context, executor Executor) (string, error)
+
+	HasTable(schema, name string) *Command
+
+	Tables(ctx context.Context, executor Executor) ([]string, error)
+
+	Inspect(ctx context.Context, executor Executor, table string) (*TableInfo, error)
 
 	CreateTable(definition *Definition) []*Command
 
-	Columns(table string) *Command
+	Columns(schema, table string) *Command
 
-	HasColumn(table, column string) *Command
+	HasColumn(schema, table, column string) *Command
 
-	ModifyColumn(table, column, rename, tpy, comment string, notNull bool, defValue interface{}) *Command
+	ModifyColumn(table, column, rename, tpy, comment string, notNull bool, defValue interface{}) []*Command
 
-	AddColumn(table, column, tpy, comment string, notNull bool, defValue interface{}) *Command
+	AddColumn(table, column, tpy, comment string, notNull bool, defValue interface{}) []*Command
 
 	DropColumn(table, column string) *Command
 
-	HasIndex(table, name string) *Command
+	HasIndex(schema, table, name string) *Command
+
+	AddIndex(table, name string, indexes []*Index) *Command
 
 	RemoveIndex(table, name string) *Command
 
-	HasForeignKey(table, name string) *Command
+	HasForeignKey(schema, table, name string) *Command
 
 	AddForeignKey(table string, key *ForeignKey) *Command
 
@@ -75,4 +85,28 @@ type Dialect interface {
 	Count(cmd *Command) *Command
 
 	Page(cmd *Command, page, size int) *Command
+
+	Limit(cmd *Command, limit, offset int) *Command
+
+	Seek(cmd *Command, keys []*Key, cursor *Cursor, size int) (*Command, error)
+
+	Literal(value interface{}) string
+
+	Savepoint(name string) *Command
+
+	RollbackTo(name string) *Command
+
+	// ReleaseSavepoint 不支持释放保存点的方言返回 nil
+	ReleaseSavepoint(name string) *Command
+
+	// Classify 按驱动的原生错误码对错误分类，无法识别时返回 KindUnknown
+	Classify(err error) ErrorKind
+
+	// Translate 将驱动的原生错误转换为 *Error，无法识别时原样返回
+	Translate(err error) error
+}
+
+// Validator 方言对表定义有额外限制时实现此接口，Parse 解析完成后调用
+type Validator interface {
+	Validate(definition *Definition) error
 }
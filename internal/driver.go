@@ -29,5 +29,6 @@ type Driver interface {
 
 	Placeholder(index int) string
 
+	// Database 查询当前 schema 的语句，结果为单行单列
 	Database() string
 }
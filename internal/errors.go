@@ -0,0 +1,111 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-02-20 11:10
+// version: 1.0.0
+// desc   : 
+
+package internal
+
+import "fmt"
+
+// ErrorKind 方言对数据库错误的分类，见 Dialect.Classify
+type ErrorKind int
+
+const (
+	// KindUnknown 未识别的错误
+	KindUnknown ErrorKind = iota
+	// KindSerialization 可串行化或快照隔离下的写冲突
+	KindSerialization
+	// KindDeadlock 死锁，数据库已回滚其中一个事务
+	KindDeadlock
+	// KindLockTimeout 等待锁超时或数据库忙
+	KindLockTimeout
+	// KindUniqueViolation 违反主键或唯一约束
+	KindUniqueViolation
+	// KindForeignKeyViolation 违反外键约束，包括引用的记录不存在和被引用的记录仍有关联
+	KindForeignKeyViolation
+	// KindNotNullViolation 非空列写入了 NULL
+	KindNotNullViolation
+	// KindCheckViolation 违反检查约束
+	KindCheckViolation
+	// KindNoRows 查询没有结果
+	KindNoRows
+	// KindTableNotFound 表不存在
+	KindTableNotFound
+)
+
+var kindNames = []string{
+	"unknown", "serialization failure", "deadlock", "lock timeout",
+	"unique violation", "foreign key violation", "not null violation", "check violation", "no rows", "table not found",
+}
+
+func (k ErrorKind) String() string {
+	if int(k) < len(kindNames) {
+		return kindNames[k]
+	}
+	return "unknown"
+}
+
+// Retryable 重新执行整个事务可能成功的错误
+func (k ErrorKind) Retryable() bool {
+	return k == KindSerialization || k == KindDeadlock || k == KindLockTimeout
+}
+
+// 用于 errors.Is 判断错误分类的哨兵错误
+var (
+	ErrUniqueViolation     = &Error{Kind: KindUniqueViolation}
+	ErrForeignKeyViolation = &Error{Kind: KindForeignKeyViolation}
+	ErrNotNullViolation    = &Error{Kind: KindNotNullViolation}
+	ErrCheckViolation      = &Error{Kind: KindCheckViolation}
+	ErrNoRows              = &Error{Kind: KindNoRows}
+	ErrTableNotFound       = &Error{Kind: KindTableNotFound}
+)
+
+// Error 由方言从驱动的原生错误转换而来，Constraint、Table、Column 为能从错误中解析出的部分，Err 为原始错误
+type Error struct {
+	Kind       ErrorKind
+	Constraint string
+	Table      string
+	Column     string
+	Err        error
+}
+
+func (e *Error) Error() string {
+	msg := "glue: " + e.Kind.String()
+	switch {
+	case e.Constraint != "":
+		msg += fmt.Sprintf(" on constraint %s", e.Constraint)
+	case e.Column != "":
+		msg += fmt.Sprintf(" on column %s", e.Column)
+	case e.Table != "":
+		msg += fmt.Sprintf(" on table %s", e.Table)
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is 与哨兵错误按分类比较
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Err == nil && t.Kind == e.Kind
+}
@@ -20,12 +20,89 @@
 
 package internal
 
-import "database/sql"
+import (
+	"context"
+	"database/sql"
+	"reflect"
+)
 
+// Executor 执行命令的上下文，*sql.DB、*sql.Tx 和 *sql.Conn 都实现了此接口
 type Executor interface {
+	ExecContext(ctx context.Context, sql string, args ...interface{}) (sql.Result, error)
+
+	QueryContext(ctx context.Context, sql string, args ...interface{}) (*sql.Rows, error)
+
+	QueryRowContext(ctx context.Context, sql string, args ...interface{}) *sql.Row
+}
+
+// LegacyExecutor 不支持 context 的执行器
+type LegacyExecutor interface {
 	Exec(sql string, args ...interface{}) (sql.Result, error)
 
 	Query(sql string, args ...interface{}) (*sql.Rows, error)
 
 	QueryRow(sql string, args ...interface{}) *sql.Row
 }
+
+type legacy struct {
+	executor LegacyExecutor
+}
+
+// Legacy 将 LegacyExecutor 适配为 Executor，ctx 只在执行前检查一次，无法中断执行中的命令。
+// *sql.Row 无法在 database/sql 之外携带错误，QueryRowContext 不检查 ctx
+func Legacy(executor LegacyExecutor) Executor {
+	return &legacy{executor: executor}
+}
+
+func (l *legacy) ExecContext(ctx context.Context, sql string, args ...interface{}) (sql.Result, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return l.executor.Exec(sql, args...)
+}
+
+func (l *legacy) QueryContext(ctx context.Context, sql string, args ...interface{}) (*sql.Rows, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return l.executor.Query(sql, args...)
+}
+
+// QueryRowContext 忽略 ctx，见 Legacy
+func (l *legacy) QueryRowContext(ctx context.Context, sql string, args ...interface{}) *sql.Row {
+	return l.executor.QueryRow(sql, args...)
+}
+
+// Result 通过 RETURNING 等方式取回主键时使用的执行结果
+type Result struct {
+	lastInsertId int64
+	rowsAffected int64
+}
+
+func NewResult(lastInsertId, rowsAffected int64) *Result {
+	return &Result{lastInsertId: lastInsertId, rowsAffected: rowsAffected}
+}
+
+// KeyResult 返回取回主键后的执行结果，key 为 Scan 的目标，整数主键同时作为 LastInsertId
+func KeyResult(key interface{}, rowsAffected int64) *Result {
+	value := reflect.ValueOf(key)
+	for value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+	var id int64
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		id = value.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		id = int64(value.Uint())
+	}
+	return NewResult(id, rowsAffected)
+}
+
+func (r *Result) LastInsertId() (int64, error) {
+	return r.lastInsertId, nil
+}
+
+func (r *Result) RowsAffected() (int64, error) {
+	return r.rowsAffected, nil
+}
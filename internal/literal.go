@@ -0,0 +1,86 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-01-28 09:40
+// version: 1.0.0
+// desc   : 
+
+package internal
+
+import (
+	"strings"
+)
+
+// Inline 将命令中的占位符替换为 literal 渲染的字面量，得到可直接执行的 sql，引号内的内容保持不变
+//
+// 仅用于生成供人工审阅的脚本，执行时仍应使用参数化的命令
+func Inline(cmd *Command, driver Driver, literal func(value interface{}) string) string {
+	args := cmd.Args()
+	if len(args) == 0 {
+		return cmd.SQL()
+	}
+	// ? 这类占位符不带序号，只能按出现顺序依次替换
+	positional := driver.Placeholder(1) == driver.Placeholder(2)
+	// 只有用方括号引用标识符的方言（mssql）才把 [ ] 当作引号，postgres 的 ARRAY[...] 和下标中仍有占位符
+	brackets := strings.HasPrefix(driver.Quote("x"), "[")
+
+	sql := cmd.SQL()
+	var sb strings.Builder
+	var quote byte
+	next := 0
+	for i := 0; i < len(sql); i++ {
+		ch := sql[i]
+		if quote != 0 {
+			if ch == quote {
+				quote = 0
+			}
+			sb.WriteByte(ch)
+			continue
+		}
+		if ch == '\'' || ch == '"' || ch == '`' || (ch == '[' && brackets) {
+			quote = ch
+			if ch == '[' {
+				quote = ']'
+			}
+			sb.WriteByte(ch)
+			continue
+		}
+		if positional {
+			if holder := driver.Placeholder(1); next < len(args) && strings.HasPrefix(sql[i:], holder) {
+				sb.WriteString(literal(args[next]))
+				i += len(holder) - 1
+				next++
+				continue
+			}
+		} else if index, holder := placeholderAt(sql[i:], driver, len(args)); index > 0 {
+			// 从大到小匹配，避免 $1 误匹配 $10
+			sb.WriteString(literal(args[index-1]))
+			i += len(holder) - 1
+			continue
+		}
+		sb.WriteByte(ch)
+	}
+	return sb.String()
+}
+
+func placeholderAt(sql string, driver Driver, count int) (int, string) {
+	for index := count; index > 0; index-- {
+		if holder := driver.Placeholder(index); strings.HasPrefix(sql, holder) {
+			return index, holder
+		}
+	}
+	return 0, ""
+}
@@ -0,0 +1,256 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-01-18 10:40
+// version: 1.0.0
+// desc   : 
+
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+var tableModelType = reflect.TypeOf(TableModel{})
+
+// Parse 将嵌入了 TableModel 的结构体解析为表定义
+//
+// 未通过 type 标签声明列类型的字段，由 dialect 根据字段类型推导，dialect 为 nil 时不推导
+func Parse(dialect Dialect, table Table) (*Definition, error) {
+	if table == nil {
+		return nil, errors.New("glue: table model is nil")
+	}
+	tp := reflect.TypeOf(table)
+	elm := tp
+	for elm.Kind() == reflect.Ptr {
+		elm = elm.Elem()
+	}
+	if elm.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("glue: table model must be a struct, got %v", tp)
+	}
+
+	name := table.TableName()
+	if name == "" {
+		name = SnakeCase(elm.Name())
+	}
+
+	definition := &Definition{
+		TableName:   name,
+		Model:       &Model{Type: tp, ElmType: elm},
+		Strategy:    table.PrimaryStrategy(),
+		Fields:      make([]*Field, 0),
+		PrimaryKeys: make([]*Field, 0),
+		Indexes:     make(map[string][]*Index),
+		ForeignKeys: make(map[string][]*ForeignKey),
+	}
+
+	if err := parseFields(dialect, definition, elm, nil); err != nil {
+		return nil, err
+	}
+	if len(definition.Fields) == 0 {
+		return nil, fmt.Errorf("glue: table model %v has no column", tp)
+	}
+	if validator, ok := dialect.(Validator); ok {
+		if err := validator.Validate(definition); err != nil {
+			return nil, err
+		}
+	}
+	return definition, nil
+}
+
+// ParseFields 解析任意结构体的列信息，不要求嵌入 TableModel，用于扫描查询结果
+func ParseFields(tp reflect.Type) ([]*Field, error) {
+	for tp.Kind() == reflect.Ptr {
+		tp = tp.Elem()
+	}
+	if tp.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("glue: %v is not a struct", tp)
+	}
+	definition := &Definition{
+		TableName:   SnakeCase(tp.Name()),
+		Fields:      make([]*Field, 0),
+		PrimaryKeys: make([]*Field, 0),
+		Indexes:     make(map[string][]*Index),
+		ForeignKeys: make(map[string][]*ForeignKey),
+	}
+	if err := parseFields(nil, definition, tp, nil); err != nil {
+		return nil, err
+	}
+	return definition.Fields, nil
+}
+
+func parseFields(dialect Dialect, definition *Definition, tp reflect.Type, index []int) error {
+	for i := 0; i < tp.NumField(); i++ {
+		sf := tp.Field(i)
+		// 未导出字段无法读写，匿名结构体除外，但未导出的嵌入指针无法初始化
+		if sf.PkgPath != "" && (!sf.Anonymous || sf.Type.Kind() == reflect.Ptr) {
+			continue
+		}
+		raw, hasTag := sf.Tag.Lookup(TagName)
+		if raw == "-" {
+			continue
+		}
+
+		path := make([]int, len(index)+1)
+		copy(path, index)
+		path[len(index)] = i
+
+		elm := sf.Type
+		for elm.Kind() == reflect.Ptr {
+			elm = elm.Elem()
+		}
+		if elm == tableModelType {
+			continue
+		}
+		// 匿名结构体字段展开到当前表中
+		if sf.Anonymous && !hasTag && elm.Kind() == reflect.Struct {
+			if err := parseFields(dialect, definition, elm, path); err != nil {
+				return err
+			}
+			continue
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+
+		tag, err := ParseTag(raw)
+		if err != nil {
+			return fmt.Errorf("%v of field %s.%s", err, tp.Name(), sf.Name)
+		}
+		field := &Field{
+			Name:       sf.Name,
+			Type:       sf.Type,
+			ElmType:    elm,
+			FieldIndex: path,
+			Column:     tag.Get(TagColumn),
+			SQLType:    tag.Get(TagType),
+			IsPrimary:  tag.Has(TagPrimary),
+			NotNull:    tag.Has(TagNotNull) || tag.Has(TagPrimary),
+			Comment:    tag.Get(TagComment),
+		}
+		if field.Column == "" {
+			field.Column = SnakeCase(sf.Name)
+		}
+		// 指针字段以 nil 表示 NULL，列总是可空的，主键除外
+		if sf.Type.Kind() == reflect.Ptr && !field.IsPrimary && tag.Has(TagNotNull) {
+			return fmt.Errorf("glue: pointer field %s.%s is nullable, remove tag 'notnull'", tp.Name(), sf.Name)
+		}
+		if field.SQLType == "" && dialect != nil {
+			if field.SQLType = dialect.SQLType(&sf); field.SQLType == "" {
+				return fmt.Errorf("glue: can not resolve column type of field %s.%s, declare it by tag 'type'", tp.Name(), sf.Name)
+			}
+		}
+		if tag.Has(TagDefault) {
+			field.Default = tag.Get(TagDefault)
+		}
+		for _, exists := range definition.Fields {
+			if exists.Column == field.Column {
+				return fmt.Errorf("glue: duplicate column '%s' of field %s.%s", field.Column, tp.Name(), sf.Name)
+			}
+		}
+
+		definition.Fields = append(definition.Fields, field)
+		if field.IsPrimary {
+			definition.PrimaryKeys = append(definition.PrimaryKeys, field)
+		}
+		if err := parseIndexes(definition, field, tag); err != nil {
+			return fmt.Errorf("%v of field %s.%s", err, tp.Name(), sf.Name)
+		}
+		if err := parseForeignKey(definition, field, tag); err != nil {
+			return fmt.Errorf("%v of field %s.%s", err, tp.Name(), sf.Name)
+		}
+	}
+	return nil
+}
+
+func parseIndexes(definition *Definition, field *Field, tag Tag) error {
+	kinds := []struct {
+		key    string
+		prefix string
+		tp     IndexType
+	}{
+		{TagIndex, "idx", IndexNormal},
+		{TagUnique, "uk", IndexUnique},
+		{TagFullText, "ft", IndexFullText},
+		{TagSpatial, "sp", IndexSpatial},
+	}
+	for _, kind := range kinds {
+		if !tag.Has(kind.key) {
+			continue
+		}
+		name := tag.Get(kind.key)
+		if name == "" {
+			name = fmt.Sprintf("%s_%s_%s", kind.prefix, definition.TableName, field.Column)
+		}
+		// 同名索引即为组合索引，类型必须一致
+		if indexes, ok := definition.Indexes[name]; ok && indexes[0].Type != kind.tp {
+			return fmt.Errorf("glue: index '%s' is declared with different types", name)
+		}
+		definition.Indexes[name] = append(definition.Indexes[name], &Index{
+			Name:   name,
+			Column: field.Column,
+			Type:   kind.tp,
+		})
+	}
+	return nil
+}
+
+// parseForeignKey 解析外键，格式为 `fk:table(column)` 或 `fk:name,table(column)`
+func parseForeignKey(definition *Definition, field *Field, tag Tag) error {
+	if !tag.Has(TagForeign) {
+		return nil
+	}
+	value := tag.Get(TagForeign)
+	name := ""
+	if idx := strings.Index(value, ","); idx >= 0 {
+		name, value = strings.TrimSpace(value[:idx]), strings.TrimSpace(value[idx+1:])
+	}
+	open, end := strings.Index(value, "("), len(value)-1
+	if open <= 0 || end <= open+1 || value[end] != ')' {
+		return fmt.Errorf("glue: malformed foreign key '%s', expect 'table(column)'", tag.Get(TagForeign))
+	}
+	if name == "" {
+		name = fmt.Sprintf("fk_%s_%s", definition.TableName, field.Column)
+	}
+	definition.ForeignKeys[name] = append(definition.ForeignKeys[name], &ForeignKey{
+		Name:      name,
+		Column:    field.Column,
+		Table:     strings.TrimSpace(value[:open]),
+		Reference: strings.TrimSpace(value[open+1 : end]),
+	})
+	return nil
+}
+
+// SnakeCase 驼峰转下划线，如 UserID -> user_id，HTTPServer -> http_server
+func SnakeCase(name string) string {
+	runes := []rune(name)
+	var sb strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) && runes[i-1] != '_' {
+				sb.WriteRune('_')
+			}
+			sb.WriteRune(unicode.ToLower(r))
+		} else {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
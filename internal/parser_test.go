@@ -0,0 +1,115 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-01-18 14:20
+// version: 1.0.0
+// desc   : 
+
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+type base struct {
+	CreatedAt time.Time `glue:"notnull;comment:创建时间"`
+}
+
+type user struct {
+	TableModel
+	base
+	ID     int64  `glue:"pk;type:BIGINT;comment:主键ID"`
+	Code   string `glue:"type:VARCHAR(100);notnull;unique:uk_user_code"`
+	Name   string `glue:"column:user_name;type:VARCHAR(255);index:idx_user_name_age"`
+	Age    int    `glue:"type:INT;notnull;default:0;index:idx_user_name_age"`
+	RoleID int64  `glue:"type:BIGINT;fk:role(id)"`
+	Ignore string `glue:"-"`
+	hidden string
+}
+
+func (*user) TableName() string {
+	return "user"
+}
+
+type broken struct {
+	TableModel
+	Name string `glue:"column"`
+}
+
+type nullable struct {
+	TableModel
+	ID   *int64  `glue:"pk;type:BIGINT"`
+	Nick *string `glue:"type:TEXT;notnull"`
+}
+
+func TestParse(t *testing.T) {
+	def, err := Parse(nil, &user{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if def.TableName != "user" {
+		t.Fatalf("unexpected table name %s", def.TableName)
+	}
+	columns := make([]string, 0)
+	for _, field := range def.Fields {
+		columns = append(columns, field.Column)
+	}
+	expected := []string{"created_at", "id", "code", "user_name", "age", "role_id"}
+	if len(columns) != len(expected) {
+		t.Fatalf("unexpected columns %v", columns)
+	}
+	for i := range expected {
+		if columns[i] != expected[i] {
+			t.Fatalf("unexpected columns %v", columns)
+		}
+	}
+	if len(def.PrimaryKeys) != 1 || def.PrimaryKeys[0].Column != "id" || !def.PrimaryKeys[0].NotNull {
+		t.Fatalf("unexpected primary keys %v", def.PrimaryKeys)
+	}
+	if len(def.Indexes["idx_user_name_age"]) != 2 || def.Indexes["uk_user_code"][0].Type != IndexUnique {
+		t.Fatalf("unexpected indexes %v", def.Indexes)
+	}
+	fk := def.ForeignKeys["fk_user_role_id"]
+	if len(fk) != 1 || fk[0].Table != "role" || fk[0].Reference != "id" {
+		t.Fatalf("unexpected foreign keys %v", def.ForeignKeys)
+	}
+	if def.Fields[0].FieldIndex[0] != 1 || def.Fields[0].FieldIndex[1] != 0 {
+		t.Fatalf("unexpected field index %v", def.Fields[0].FieldIndex)
+	}
+
+	if _, err := Parse(nil, &broken{}); err == nil {
+		t.Fatal("expect error of malformed tag")
+	}
+	if _, err := Parse(nil, &nullable{}); err == nil {
+		t.Fatal("expect error of not null pointer field")
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	cases := map[string]string{
+		"ID":         "id",
+		"UserID":     "user_id",
+		"HTTPServer": "http_server",
+		"CreatedAt":  "created_at",
+		"Address2":   "address2",
+	}
+	for name, expected := range cases {
+		if actual := SnakeCase(name); actual != expected {
+			t.Errorf("SnakeCase(%s) = %s, expected %s", name, actual, expected)
+		}
+	}
+}
@@ -24,3 +24,24 @@ type Strategy interface {
 
 	Primary() interface{}
 }
+
+// Expression 主键策略返回此类型时，插入语句直接写入表达式而不绑定为参数，如取序列的下一个值，
+// 生成的主键通过方言的 RETURNING 等子句取回
+type Expression interface {
+	Expression(dialect Dialect) (string, error)
+}
+
+// Declaration 主键策略对主键列建表语句的影响
+type Declaration struct {
+	// Identity 主键列使用方言的自增语法
+	Identity bool
+	// Default 主键列的默认值表达式，原样写入 DEFAULT 子句，如 gen_random_uuid()
+	Default string
+	// Commands 在建表语句之前执行，如创建序列
+	Commands []*Command
+}
+
+// Declarer 主键策略实现此接口以按方言声明主键列，返回 nil 或未实现时不影响建表语句
+type Declarer interface {
+	Declare(dialect Dialect) *Declaration
+}
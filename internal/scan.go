@@ -0,0 +1,208 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-01-25 10:15
+// version: 1.0.0
+// desc   : 
+
+package internal
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// UnknownColumn 结果集中存在结构体没有对应字段的列时的处理方式
+type UnknownColumn int
+
+const (
+	UnknownIgnore UnknownColumn = iota
+	UnknownError
+)
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	mapType     = reflect.TypeOf(map[string]interface{}{})
+	fieldsCache sync.Map
+)
+
+// ScanOne 扫描第一行到 dest，dest 可以是结构体指针、*map[string]interface{} 或单列的基础类型指针，
+// 没有数据时返回 sql.ErrNoRows，rows 会被关闭
+func ScanOne(rows *sql.Rows, dest interface{}, unknown UnknownColumn) error {
+	defer rows.Close()
+	value := reflect.ValueOf(dest)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return fmt.Errorf("glue: scan destination must be a non-nil pointer, got %T", dest)
+	}
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	if err := scanRow(rows, columns, value.Elem(), unknown); err != nil {
+		return err
+	}
+	return rows.Err()
+}
+
+// ScanAll 扫描所有行到 dest，dest 为切片指针，元素可以是结构体、结构体指针、map[string]interface{} 或基础类型，
+// rows 会被关闭
+func ScanAll(rows *sql.Rows, dest interface{}, unknown UnknownColumn) error {
+	defer rows.Close()
+	value := reflect.ValueOf(dest)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("glue: scan destination must be a pointer to slice, got %T", dest)
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	slice := value.Elem()
+	elmType := slice.Type().Elem()
+	isPtr := elmType.Kind() == reflect.Ptr
+	if isPtr {
+		elmType = elmType.Elem()
+	}
+	result := reflect.MakeSlice(slice.Type(), 0, 0)
+	for rows.Next() {
+		elm := reflect.New(elmType)
+		if err := scanRow(rows, columns, elm.Elem(), unknown); err != nil {
+			return err
+		}
+		if isPtr {
+			result = reflect.Append(result, elm)
+		} else {
+			result = reflect.Append(result, elm.Elem())
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	slice.Set(result)
+	return nil
+}
+
+func scanRow(rows *sql.Rows, columns []string, value reflect.Value, unknown UnknownColumn) error {
+	tp := value.Type()
+	switch {
+	case tp == mapType:
+		return scanMap(rows, columns, value)
+	case tp.Kind() == reflect.Struct && tp != timeType && !reflect.PtrTo(tp).Implements(scannerType):
+		return scanStruct(rows, columns, value, unknown)
+	}
+	if len(columns) != 1 {
+		return fmt.Errorf("glue: can not scan %d columns into %v", len(columns), tp)
+	}
+	return rows.Scan(value.Addr().Interface())
+}
+
+func scanMap(rows *sql.Rows, columns []string, value reflect.Value) error {
+	values := make([]interface{}, len(columns))
+	holders := make([]interface{}, len(columns))
+	for i := range values {
+		holders[i] = &values[i]
+	}
+	if err := rows.Scan(holders...); err != nil {
+		return err
+	}
+	result := make(map[string]interface{}, len(columns))
+	for i, column := range columns {
+		result[column] = values[i]
+	}
+	value.Set(reflect.ValueOf(result))
+	return nil
+}
+
+func scanStruct(rows *sql.Rows, columns []string, value reflect.Value, unknown UnknownColumn) error {
+	fields, err := columnFields(value.Type())
+	if err != nil {
+		return err
+	}
+	holders := make([]interface{}, len(columns))
+	for i, column := range columns {
+		field, ok := fields[strings.ToLower(column)]
+		if !ok {
+			if unknown == UnknownError {
+				return fmt.Errorf("glue: column '%s' has no matching field in %v", column, value.Type())
+			}
+			holders[i] = new(interface{})
+			continue
+		}
+		holders[i] = SettableOf(value, field).Addr().Interface()
+	}
+	return rows.Scan(holders...)
+}
+
+// columnFields 结构体的列名（小写）到字段的映射，按类型缓存
+func columnFields(tp reflect.Type) (map[string]*Field, error) {
+	if cached, ok := fieldsCache.Load(tp); ok {
+		return cached.(map[string]*Field), nil
+	}
+	fields, err := ParseFields(tp)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, errors.New("glue: " + tp.String() + " has no column")
+	}
+	result := make(map[string]*Field, len(fields))
+	for _, field := range fields {
+		result[strings.ToLower(field.Column)] = field
+	}
+	fieldsCache.Store(tp, result)
+	return result, nil
+}
+
+// FieldOf 按索引路径取字段，路径上的嵌入指针为 nil 时返回无效值
+func FieldOf(model reflect.Value, field *Field) reflect.Value {
+	value := model
+	for i, idx := range field.FieldIndex {
+		if i > 0 && value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				return reflect.Value{}
+			}
+			value = value.Elem()
+		}
+		value = value.Field(idx)
+	}
+	return value
+}
+
+// SettableOf 按索引路径取字段，路径上为 nil 的嵌入指针会被初始化
+func SettableOf(model reflect.Value, field *Field) reflect.Value {
+	value := model
+	for i, idx := range field.FieldIndex {
+		if i > 0 && value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				value.Set(reflect.New(value.Type().Elem()))
+			}
+			value = value.Elem()
+		}
+		value = value.Field(idx)
+	}
+	return value
+}
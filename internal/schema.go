@@ -0,0 +1,105 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-01-30 09:20
+// version: 1.0.0
+// desc   : 
+
+package internal
+
+// TableInfo 从数据库中读取的表结构
+type TableInfo struct {
+	Name        string
+	Columns     []*ColumnInfo
+	PrimaryKeys []string
+	Indexes     []*IndexInfo
+	ForeignKeys []*ForeignKeyInfo
+}
+
+type ColumnInfo struct {
+	Name     string
+	Type     string
+	Nullable bool
+	// Default 为 nil 表示没有默认值
+	Default  *string
+	Comment  string
+	Ordinal  int
+	Identity bool
+}
+
+type IndexInfo struct {
+	Name    string
+	Columns []string
+	Type    IndexType
+}
+
+type ForeignKeyInfo struct {
+	Name       string
+	Columns    []string
+	Table      string
+	References []string
+}
+
+func (t *TableInfo) Column(name string) *ColumnInfo {
+	for _, column := range t.Columns {
+		if column.Name == name {
+			return column
+		}
+	}
+	return nil
+}
+
+// Definition 还原为表定义，自增列记录在 ColumnInfo.Identity 中，Strategy 需由调用方设置
+func (t *TableInfo) Definition() *Definition {
+	definition := &Definition{
+		TableName:   t.Name,
+		Fields:      make([]*Field, 0, len(t.Columns)),
+		PrimaryKeys: make([]*Field, 0, len(t.PrimaryKeys)),
+		Indexes:     make(map[string][]*Index),
+		ForeignKeys: make(map[string][]*ForeignKey),
+	}
+	fields := make(map[string]*Field)
+	for _, column := range t.Columns {
+		field := &Field{
+			Column:  column.Name,
+			SQLType: column.Type,
+			NotNull: !column.Nullable,
+			Comment: column.Comment,
+		}
+		if column.Default != nil {
+			field.Default = *column.Default
+		}
+		fields[column.Name] = field
+		definition.Fields = append(definition.Fields, field)
+	}
+	for _, name := range t.PrimaryKeys {
+		if field, ok := fields[name]; ok {
+			field.IsPrimary = true
+			definition.PrimaryKeys = append(definition.PrimaryKeys, field)
+		}
+	}
+	for _, index := range t.Indexes {
+		for _, column := range index.Columns {
+			definition.Indexes[index.Name] = append(definition.Indexes[index.Name], &Index{Name: index.Name, Column: column, Type: index.Type})
+		}
+	}
+	for _, fk := range t.ForeignKeys {
+		for i, column := range fk.Columns {
+			definition.ForeignKeys[fk.Name] = append(definition.ForeignKeys[fk.Name], &ForeignKey{Name: fk.Name, Column: column, Table: fk.Table, Reference: fk.References[i]})
+		}
+	}
+	return definition
+}
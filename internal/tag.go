@@ -0,0 +1,114 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-01-18 10:12
+// version: 1.0.0
+// desc   : 
+
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+const TagName = "glue"
+
+const (
+	TagColumn   = "column"
+	TagType     = "type"
+	TagSize     = "size"
+	TagDecimal  = "decimal"
+	TagPrimary  = "pk"
+	TagNotNull  = "notnull"
+	TagDefault  = "default"
+	TagComment  = "comment"
+	TagIndex    = "index"
+	TagUnique   = "unique"
+	TagFullText = "fulltext"
+	TagSpatial  = "spatial"
+	TagForeign  = "fk"
+)
+
+// 每个 key 的取值要求：值必填、不允许有值、值可选
+const (
+	tagValueRequired = iota
+	tagValueNone
+	tagValueOptional
+)
+
+var tagKeys = map[string]int{
+	TagColumn:   tagValueRequired,
+	TagType:     tagValueRequired,
+	TagSize:     tagValueRequired,
+	TagDecimal:  tagValueRequired,
+	TagPrimary:  tagValueNone,
+	TagNotNull:  tagValueNone,
+	TagDefault:  tagValueRequired,
+	TagComment:  tagValueRequired,
+	TagIndex:    tagValueOptional,
+	TagUnique:   tagValueOptional,
+	TagFullText: tagValueOptional,
+	TagSpatial:  tagValueOptional,
+	TagForeign:  tagValueRequired,
+}
+
+// Tag 解析后的 glue 标签，如 `glue:"column:user_name;type:VARCHAR(64);notnull;index:idx_name"`
+type Tag map[string]string
+
+func ParseTag(tag string) (Tag, error) {
+	result := make(Tag)
+	for _, item := range strings.Split(tag, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		key, value, hasValue := item, "", false
+		if idx := strings.Index(item, ":"); idx >= 0 {
+			key, value, hasValue = strings.TrimSpace(item[:idx]), strings.TrimSpace(item[idx+1:]), true
+		}
+		key = strings.ToLower(key)
+
+		rule, ok := tagKeys[key]
+		if !ok {
+			return nil, fmt.Errorf("glue: unknown tag key '%s'", key)
+		}
+		if _, ok := result[key]; ok {
+			return nil, fmt.Errorf("glue: duplicate tag key '%s'", key)
+		}
+		switch rule {
+		case tagValueRequired:
+			if value == "" {
+				return nil, fmt.Errorf("glue: tag key '%s' requires a value", key)
+			}
+		case tagValueNone:
+			if hasValue {
+				return nil, fmt.Errorf("glue: tag key '%s' does not accept a value", key)
+			}
+		}
+		result[key] = value
+	}
+	return result, nil
+}
+
+func (t Tag) Has(key string) bool {
+	_, ok := t[key]
+	return ok
+}
+
+func (t Tag) Get(key string) string {
+	return t[key]
+}
@@ -24,5 +24,13 @@ type ExecValue struct {
 	Table   string
 	Columns []string
 	Values  []interface{}
+	Where   Condition
 	Type    ExecType
+	// Primary 由数据库生成、插入后需要取回的主键列
+	Primary string
+}
+
+// Valid 判断列与值是否一一对应，方言对不合法的 ExecValue 不生成语句
+func (v *ExecValue) Valid() bool {
+	return v != nil && len(v.Columns) == len(v.Values)
 }
@@ -0,0 +1,325 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-01-27 10:30
+// version: 1.0.0
+// desc   : 
+
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"github.com/yhyzgn/glue/dialect"
+	"github.com/yhyzgn/glue/internal"
+	"sort"
+	"strings"
+)
+
+type Action string
+
+type Object string
+
+const (
+	ActionCreate Action = "create"
+	ActionAdd    Action = "add"
+	ActionModify Action = "modify"
+	ActionDrop   Action = "drop"
+)
+
+const (
+	ObjectTable      Object = "table"
+	ObjectColumn     Object = "column"
+	ObjectIndex      Object = "index"
+	ObjectForeignKey Object = "foreign key"
+)
+
+// Change 表结构的一项变更及其对应的命令
+type Change struct {
+	Action   Action
+	Object   Object
+	Table    string
+	Name     string
+	Commands []*internal.Command
+	// Unsafe 可能丢失数据或因已有数据失败的变更，如修改列类型、可空性和删除列，安全模式下只报告不执行
+	Unsafe bool
+}
+
+type Migrator struct {
+	dialect  internal.Dialect
+	executor internal.Executor
+	safe     bool
+}
+
+func New(dialect internal.Dialect, executor internal.Executor) *Migrator {
+	return &Migrator{dialect: dialect, executor: executor, safe: true}
+}
+
+// Safe 安全模式下不执行 Unsafe 的变更，只记录在 Plan.Skipped 中，默认开启
+func (m *Migrator) Safe(safe bool) *Migrator {
+	m.safe = safe
+	return m
+}
+
+// AutoMigrate 使用安全模式同步表结构
+func AutoMigrate(ctx context.Context, dialect internal.Dialect, executor internal.Executor, definitions ...*internal.Definition) error {
+	return New(dialect, executor).AutoMigrate(ctx, definitions...)
+}
+
+// AutoMigrate 对比表定义与数据库中的表结构，按外键依赖顺序创建缺失的表，补充或修改列、索引和外键
+func (m *Migrator) AutoMigrate(ctx context.Context, definitions ...*internal.Definition) error {
+	plan, err := m.Plan(ctx, definitions...)
+	if err != nil {
+		return err
+	}
+	return m.Apply(ctx, plan)
+}
+
+// Diff 计算表定义与数据库之间的差异，不执行任何变更，结果包含 Unsafe 的变更
+func (m *Migrator) Diff(ctx context.Context, definitions ...*internal.Definition) ([]*Change, error) {
+	ordered, err := sortByDependency(definitions)
+	if err != nil {
+		return nil, err
+	}
+	tables, err := m.dialect.Tables(ctx, m.executor)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]bool)
+	for _, table := range tables {
+		existing[strings.ToLower(table)] = true
+	}
+
+	changes := make([]*Change, 0)
+	for _, def := range ordered {
+		var info *internal.TableInfo
+		if existing[strings.ToLower(def.TableName)] {
+			if info, err = m.dialect.Inspect(ctx, m.executor, def.TableName); err != nil {
+				return nil, err
+			}
+		}
+		if info == nil {
+			changes = appendChange(changes, &Change{Action: ActionCreate, Object: ObjectTable, Table: def.TableName, Name: def.TableName, Commands: m.dialect.CreateTable(def)})
+			continue
+		}
+		changes = append(changes, m.diffTable(def, info)...)
+	}
+	return changes, nil
+}
+
+func (m *Migrator) diffTable(def *internal.Definition, info *internal.TableInfo) []*Change {
+	table := def.TableName
+	changes := make([]*Change, 0)
+
+	columns := make(map[string]*internal.ColumnInfo)
+	for _, column := range info.Columns {
+		columns[strings.ToLower(column.Name)] = column
+	}
+	defined := make(map[string]bool)
+	for _, field := range def.Fields {
+		defined[strings.ToLower(field.Column)] = true
+		col, ok := columns[strings.ToLower(field.Column)]
+		if !ok {
+			commands := m.dialect.AddColumn(table, field.Column, field.SQLType, field.Comment, field.NotNull, field.Default)
+			changes = appendChange(changes, &Change{Action: ActionAdd, Object: ObjectColumn, Table: table, Name: field.Column, Commands: commands})
+			continue
+		}
+		// 自增列的类型和可空性由数据库决定，如 sqlite 的自增主键必须是 INTEGER
+		if col.Identity {
+			continue
+		}
+		// 修改类型可能截断数据，改为非空可能因已有的 NULL 失败，
+		// 方言不支持修改列时没有命令，仍记录变更，执行时返回错误
+		if !sameType(m.dialect, field.SQLType, col.Type) || field.NotNull == col.Nullable {
+			commands := m.dialect.ModifyColumn(table, field.Column, field.Column, field.SQLType, field.Comment, field.NotNull, field.Default)
+			changes = append(changes, &Change{Action: ActionModify, Object: ObjectColumn, Table: table, Name: field.Column, Commands: commands, Unsafe: true})
+		}
+	}
+	for _, column := range info.Columns {
+		if !defined[strings.ToLower(column.Name)] {
+			changes = appendChange(changes, &Change{Action: ActionDrop, Object: ObjectColumn, Table: table, Name: column.Name, Commands: []*internal.Command{m.dialect.DropColumn(table, column.Name)}, Unsafe: true})
+		}
+	}
+
+	indexes := make(map[string]bool)
+	for _, index := range info.Indexes {
+		indexes[strings.ToLower(index.Name)] = true
+	}
+	for _, name := range sortedIndexes(def.Indexes) {
+		if !indexes[strings.ToLower(name)] {
+			changes = appendChange(changes, &Change{Action: ActionAdd, Object: ObjectIndex, Table: table, Name: name, Commands: []*internal.Command{m.dialect.AddIndex(table, name, def.Indexes[name])}})
+		}
+	}
+
+	// sqlite 等数据库不保存外键名，名称不一致时再按列和被引用的表匹配
+	fks := make(map[string]bool)
+	references := make(map[string]bool)
+	for _, fk := range info.ForeignKeys {
+		fks[strings.ToLower(fk.Name)] = true
+		references[reference(fk.Table, fk.Columns)] = true
+	}
+	for _, name := range sortedForeignKeys(def.ForeignKeys) {
+		columns := make([]string, len(def.ForeignKeys[name]))
+		for i, fk := range def.ForeignKeys[name] {
+			columns[i] = fk.Column
+		}
+		if !fks[strings.ToLower(name)] && !references[reference(def.ForeignKeys[name][0].Table, columns)] {
+			changes = appendChange(changes, &Change{Action: ActionAdd, Object: ObjectForeignKey, Table: table, Name: name, Commands: []*internal.Command{m.dialect.AddForeignKey(table, mergeForeignKeys(def.ForeignKeys[name]))}})
+		}
+	}
+	return changes
+}
+
+// sortByDependency 按外键依赖排序，被引用的表排在前面
+func sortByDependency(definitions []*internal.Definition) ([]*internal.Definition, error) {
+	byName := make(map[string]*internal.Definition)
+	for _, def := range definitions {
+		byName[def.TableName] = def
+	}
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	states := make(map[string]int)
+	result := make([]*internal.Definition, 0, len(definitions))
+
+	var visit func(def *internal.Definition) error
+	visit = func(def *internal.Definition) error {
+		switch states[def.TableName] {
+		case visiting:
+			return fmt.Errorf("glue: circular foreign key reference on table '%s'", def.TableName)
+		case visited:
+			return nil
+		}
+		states[def.TableName] = visiting
+		for _, name := range sortedForeignKeys(def.ForeignKeys) {
+			for _, fk := range def.ForeignKeys[name] {
+				if dep, ok := byName[fk.Table]; ok && dep != def {
+					if err := visit(dep); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		states[def.TableName] = visited
+		result = append(result, def)
+		return nil
+	}
+	for _, def := range definitions {
+		if err := visit(def); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// typeAliases 数据库返回的类型全称与建表时使用的类型名的对应关系
+var typeAliases = map[string]string{
+	"charactervarying":         "varchar",
+	"character":                "char",
+	"timestampwithouttimezone": "timestamp",
+	"doubleprecision":          "double",
+	"integer":                  "int",
+	"int4":                     "int",
+	"int8":                     "bigint",
+	"int2":                     "smallint",
+	"boolean":                  "bool",
+}
+
+// sameType 比较列类型，先按方言的 dialect.TypeNormalizer 统一写法，再忽略大小写、空白和类型别名，
+// 任意一方只有类型名时只比较类型名
+func sameType(dl internal.Dialect, defined, actual string) bool {
+	if actual == "" {
+		return true
+	}
+	if normalizer, ok := dl.(dialect.TypeNormalizer); ok {
+		defined, actual = normalizer.NormalizeType(defined), normalizer.NormalizeType(actual)
+	}
+	defined, actual = normalizeType(defined), normalizeType(actual)
+	if defined == actual {
+		return true
+	}
+	definedBase, definedArgs := splitType(defined)
+	actualBase, actualArgs := splitType(actual)
+	if definedBase != actualBase {
+		return false
+	}
+	return definedArgs == "" || actualArgs == ""
+}
+
+func normalizeType(tpy string) string {
+	tpy = strings.Join(strings.Fields(strings.ToLower(tpy)), "")
+	base, args := splitType(tpy)
+	if alias, ok := typeAliases[base]; ok {
+		base = alias
+	}
+	return base + args
+}
+
+func splitType(tpy string) (base, args string) {
+	if idx := strings.Index(tpy, "("); idx >= 0 {
+		return tpy[:idx], tpy[idx:]
+	}
+	return tpy, ""
+}
+
+// appendChange 忽略方言不支持而返回 nil 的命令，如 tidb 不支持外键
+func appendChange(changes []*Change, change *Change) []*Change {
+	commands := make([]*internal.Command, 0, len(change.Commands))
+	for _, cmd := range change.Commands {
+		if cmd != nil {
+			commands = append(commands, cmd)
+		}
+	}
+	if len(commands) == 0 {
+		return changes
+	}
+	change.Commands = commands
+	return append(changes, change)
+}
+
+// reference 外键引用的表和外键列，忽略大小写
+func reference(table string, columns []string) string {
+	return strings.ToLower(table + "(" + strings.Join(columns, ",") + ")")
+}
+
+func mergeForeignKeys(fks []*internal.ForeignKey) *internal.ForeignKey {
+	columns := make([]string, len(fks))
+	references := make([]string, len(fks))
+	for i, fk := range fks {
+		columns[i] = fk.Column
+		references[i] = fk.Reference
+	}
+	return &internal.ForeignKey{Name: fks[0].Name, Column: strings.Join(columns, ", "), Table: fks[0].Table, Reference: strings.Join(references, ", ")}
+}
+
+func sortedIndexes(indexes map[string][]*internal.Index) []string {
+	keys := make([]string, 0, len(indexes))
+	for key := range indexes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedForeignKeys(fks map[string][]*internal.ForeignKey) []string {
+	keys := make([]string, 0, len(fks))
+	for key := range fks {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
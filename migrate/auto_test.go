@@ -0,0 +1,308 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-01-27 11:20
+// version: 1.0.0
+// desc   : 
+
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"github.com/yhyzgn/glue/dialect/clickhouse"
+	"github.com/yhyzgn/glue/dialect/mssql"
+	"github.com/yhyzgn/glue/dialect/mysql"
+	"github.com/yhyzgn/glue/dialect/postgres"
+	"github.com/yhyzgn/glue/dialect/sqlite"
+	"github.com/yhyzgn/glue/internal"
+	"github.com/yhyzgn/glue/primary"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type team struct {
+	internal.TableModel
+	ID   int64  `glue:"pk"`
+	Name string `glue:"size:64;notnull;unique"`
+}
+
+type player struct {
+	internal.TableModel
+	ID     int64  `glue:"pk"`
+	TeamID int64  `glue:"notnull;fk:team(id)"`
+	Name   string `glue:"size:64;index"`
+}
+
+type playerWithAge struct {
+	player
+	Age int `glue:"notnull;default:18;comment:it's the age"`
+}
+
+type playerStrict struct {
+	internal.TableModel
+	ID   int64  `glue:"pk"`
+	Name string `glue:"size:64;notnull;index"`
+}
+
+func (*playerStrict) TableName() string {
+	return "player"
+}
+
+func (*team) PrimaryStrategy() internal.Strategy {
+	return &primary.AutoIncrement{}
+}
+
+func (*player) TableName() string {
+	return "player"
+}
+
+func (*player) PrimaryStrategy() internal.Strategy {
+	return &primary.AutoIncrement{}
+}
+
+func TestAutoMigrate(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	ctx := context.Background()
+	dl := sqlite.Dialect()
+	definitions := make([]*internal.Definition, 0)
+	for _, table := range []internal.Table{&player{}, &team{}} {
+		def, err := internal.Parse(dl, table)
+		if err != nil {
+			t.Fatal(err)
+		}
+		definitions = append(definitions, def)
+	}
+	if err := AutoMigrate(ctx, dl, db, definitions...); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := dl.Inspect(ctx, db, "player")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(info.Columns) != 3 || !info.Columns[0].Identity || info.Column("team_id").Nullable || !info.Column("name").Nullable {
+		t.Errorf("unexpected columns %+v", info.Columns)
+	}
+	if len(info.PrimaryKeys) != 1 || info.PrimaryKeys[0] != "id" {
+		t.Errorf("unexpected primary keys %v", info.PrimaryKeys)
+	}
+	if len(info.Indexes) != 1 || info.Indexes[0].Name != "idx_player_name" || info.Indexes[0].Type != internal.IndexNormal {
+		t.Errorf("unexpected indexes %+v", info.Indexes)
+	}
+	if len(info.ForeignKeys) != 1 || info.ForeignKeys[0].Name != "fk_player_team_id" || info.ForeignKeys[0].Table != "team" || info.ForeignKeys[0].References[0] != "id" {
+		t.Errorf("unexpected foreign keys %+v", info.ForeignKeys)
+	}
+	if def := info.Definition(); len(def.Fields) != 3 || len(def.PrimaryKeys) != 1 || len(def.Indexes["idx_player_name"]) != 1 || len(def.ForeignKeys["fk_player_team_id"]) != 1 {
+		t.Errorf("unexpected definition %+v", def)
+	}
+
+	plan, err := New(dl, db).Plan(ctx, definitions...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plan.Empty() || len(plan.Skipped) != 0 {
+		t.Errorf("unexpected changes:\n%s", plan.Script())
+	}
+
+	def, err := internal.Parse(dl, &playerWithAge{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan, err = New(dl, db).Plan(ctx, def); err != nil {
+		t.Fatal(err)
+	}
+	if summary := plan.Summary(); len(summary.Added) != 1 || summary.Added[0] != "column player.age" || len(plan.Changes) != 1 {
+		t.Errorf("unexpected plan %s:\n%s", summary, plan.Script())
+	}
+	if err := New(dl, db).Apply(ctx, plan); err != nil {
+		t.Fatal(err)
+	}
+	if info, err = dl.Inspect(ctx, db, "player"); err != nil || info.Column("age") == nil || info.Column("age").Nullable {
+		t.Fatalf("column age not added: %v", err)
+	}
+	if plan, err = New(dl, db).Plan(ctx, def); err != nil || !plan.Empty() || len(plan.Skipped) != 0 {
+		t.Errorf("unexpected plan after adding column: %v\n%s", err, plan.Script())
+	}
+
+	// 安全模式下修改列和删除列只报告不执行
+	if def, err = internal.Parse(dl, &playerStrict{}); err != nil {
+		t.Fatal(err)
+	}
+	if plan, err = New(dl, db).Plan(ctx, def); err != nil {
+		t.Fatal(err)
+	}
+	if summary := plan.Summary(); !plan.Empty() || len(summary.Skipped) != 3 || summary.Skipped[0] != "modify column player.name" || summary.Skipped[1] != "drop column player.team_id" || summary.Skipped[2] != "drop column player.age" {
+		t.Errorf("unexpected safe plan %s:\n%s", summary, plan.Script())
+	}
+	if err := New(dl, db).AutoMigrate(ctx, def); err != nil {
+		t.Fatal(err)
+	}
+	if plan, err = New(dl, db).Safe(false).Plan(ctx, def); err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Changes) != 3 || len(plan.Skipped) != 0 {
+		t.Errorf("unexpected unsafe plan:\n%s", plan.Script())
+	}
+	// sqlite 不能修改列，不安全模式下执行时报错
+	if err := New(dl, db).Apply(ctx, plan); err == nil || !strings.Contains(err.Error(), "not supported by sqlite") {
+		t.Errorf("sqlite modify column should be rejected: %v", err)
+	}
+	if !strings.Contains(plan.Script(), "-- modify column player.name\n-- not supported by sqlite") {
+		t.Errorf("unexpected script:\n%s", plan.Script())
+	}
+}
+
+type owner struct {
+	internal.TableModel
+	ID int64 `glue:"pk"`
+}
+
+type pet struct {
+	internal.TableModel
+	ID      int64 `glue:"pk"`
+	OwnerID int64 `glue:"fk:pet_owner,owner(id)"`
+}
+
+func TestAutoMigrateNamedForeignKey(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	ctx := context.Background()
+	dl := sqlite.Dialect()
+	definitions := make([]*internal.Definition, 0)
+	for _, table := range []internal.Table{&pet{}, &owner{}} {
+		def, err := internal.Parse(dl, table)
+		if err != nil {
+			t.Fatal(err)
+		}
+		definitions = append(definitions, def)
+	}
+	// sqlite 读不到外键名，再次同步时按列和被引用的表识别已有的外键
+	for i := 0; i < 2; i++ {
+		if err := AutoMigrate(ctx, dl, db, definitions...); err != nil {
+			t.Fatalf("migrate %d: %v", i+1, err)
+		}
+	}
+	plan, err := New(dl, db).Plan(ctx, definitions...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plan.Empty() {
+		t.Errorf("unexpected changes:\n%s", plan.Script())
+	}
+}
+
+func TestSortByDependency(t *testing.T) {
+	user := &internal.Definition{TableName: "user"}
+	order := &internal.Definition{TableName: "order", ForeignKeys: map[string][]*internal.ForeignKey{
+		"fk_order_user_id": {{Name: "fk_order_user_id", Column: "user_id", Table: "user", Reference: "id"}},
+	}}
+	item := &internal.Definition{TableName: "item", ForeignKeys: map[string][]*internal.ForeignKey{
+		"fk_item_order_id": {{Name: "fk_item_order_id", Column: "order_id", Table: "order", Reference: "id"}},
+	}}
+
+	sorted, err := sortByDependency([]*internal.Definition{item, order, user})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sorted) != 3 || sorted[0] != user || sorted[1] != order || sorted[2] != item {
+		t.Errorf("unexpected order %s, %s, %s", sorted[0].TableName, sorted[1].TableName, sorted[2].TableName)
+	}
+
+	user.ForeignKeys = map[string][]*internal.ForeignKey{
+		"fk_user_item_id": {{Name: "fk_user_item_id", Column: "item_id", Table: "item", Reference: "id"}},
+	}
+	if _, err := sortByDependency([]*internal.Definition{item, order, user}); err == nil {
+		t.Error("expect error of circular reference")
+	}
+}
+
+func TestSameType(t *testing.T) {
+	cases := []struct {
+		defined string
+		actual  string
+		same    bool
+	}{
+		{"VARCHAR(255)", "varchar(255)", true},
+		{"DECIMAL(10, 2)", "decimal(10,2)", true},
+		{"VARCHAR(255)", "varchar", true},
+		{"VARCHAR(255)", "varchar(64)", false},
+		{"BIGINT", "int", false},
+		{"BIGINT", "bigint(20)", true},
+		{"VARCHAR(64)", "character varying(64)", true},
+		{"TIMESTAMP", "timestamp without time zone", true},
+		{"TEXT", "", true},
+	}
+	for _, c := range cases {
+		if same := sameType(sqlite.Dialect(), c.defined, c.actual); same != c.same {
+			t.Errorf("sameType(%q, %q) = %v, want %v", c.defined, c.actual, same, c.same)
+		}
+	}
+}
+
+// TestSameTypeOfDialects 各方言建表时的类型与数据库返回的类型一致，新建的表不会产生修改列的变更
+func TestSameTypeOfDialects(t *testing.T) {
+	type model struct {
+		Flag     bool
+		Tiny     int8
+		Count    int
+		Size     uint64
+		Ratio    float32
+		Score    float64
+		Name     string
+		Title    string `glue:"size:64"`
+		Body     string `glue:"size:100000"`
+		Data     []byte
+		At       time.Time
+		Nickname *string
+		Age      sql.NullInt32
+		Price    float64 `glue:"decimal:10,2"`
+	}
+	cases := []struct {
+		dialect internal.Dialect
+		actual  []string
+	}{
+		{mysql.Dialect(), []string{"tinyint(1)", "tinyint(4)", "bigint(20)", "bigint(20) unsigned", "float", "double", "varchar(255)", "varchar(64)", "mediumtext", "longblob", "datetime", "varchar(255)", "int(11)", "decimal(10,2)"}},
+		{postgres.Dialect(), []string{"boolean", "smallint", "bigint", "numeric(20,0)", "real", "double precision", "character varying(255)", "character varying(64)", "character varying(100000)", "bytea", "timestamp with time zone", "character varying(255)", "integer", "numeric(10,2)"}},
+		{mssql.Dialect(), []string{"bit", "smallint", "bigint", "numeric(20,0)", "real", "float", "nvarchar(255)", "nvarchar(64)", "nvarchar(MAX)", "varbinary(MAX)", "datetime2", "nvarchar(255)", "int", "decimal(10,2)"}},
+		{clickhouse.Dialect(), []string{"UInt8", "Int8", "Int64", "UInt64", "Float32", "Float64", "Nullable(String)", "String", "String", "String", "DateTime", "Nullable(String)", "Nullable(Int32)", "Decimal(10, 2)"}},
+	}
+	tp := reflect.TypeOf(model{})
+	for _, cs := range cases {
+		for i := 0; i < tp.NumField(); i++ {
+			field := tp.Field(i)
+			if defined := cs.dialect.SQLType(&field); !sameType(cs.dialect, defined, cs.actual[i]) {
+				t.Errorf("%s type %s of %s differs from %s", cs.dialect.Name(), defined, field.Name, cs.actual[i])
+			}
+		}
+	}
+	if sameType(mysql.Dialect(), "INT", "bigint(20)") || sameType(postgres.Dialect(), "NUMERIC(20)", "numeric(20,2)") {
+		t.Error("different types should not be the same")
+	}
+}
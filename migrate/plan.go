@@ -0,0 +1,162 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-01-28 10:50
+// version: 1.0.0
+// desc   : 
+
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"github.com/yhyzgn/glue/internal"
+	"io"
+	"strings"
+)
+
+// Plan 待执行的变更，可导出为 sql 脚本供审阅后再执行
+type Plan struct {
+	dialect internal.Dialect
+	Changes []*Change
+	// Skipped 安全模式下跳过的 Unsafe 变更，不会执行，需审阅后手动处理
+	Skipped []*Change
+}
+
+// Summary 按操作分类的变更对象，如 "table user"、"column user.email"
+type Summary struct {
+	Created  []string
+	Added    []string
+	Modified []string
+	Dropped  []string
+	Skipped  []string
+}
+
+// Plan 计算变更但不执行，安全模式下 Unsafe 的变更放入 Skipped
+func (m *Migrator) Plan(ctx context.Context, definitions ...*internal.Definition) (*Plan, error) {
+	changes, err := m.Diff(ctx, definitions...)
+	if err != nil {
+		return nil, err
+	}
+	plan := &Plan{dialect: m.dialect, Changes: make([]*Change, 0, len(changes))}
+	for _, change := range changes {
+		if change.Unsafe && m.safe {
+			plan.Skipped = append(plan.Skipped, change)
+		} else {
+			plan.Changes = append(plan.Changes, change)
+		}
+	}
+	return plan, nil
+}
+
+// Apply 依次执行计划中的命令
+func (m *Migrator) Apply(ctx context.Context, plan *Plan) error {
+	for _, change := range plan.Changes {
+		if len(change.Commands) == 0 {
+			return fmt.Errorf("glue: %s %s '%s' of table '%s' is not supported by %s", change.Action, change.Object, change.Name, change.Table, m.dialect.Name())
+		}
+		for _, cmd := range change.Commands {
+			if _, err := m.executor.ExecContext(ctx, cmd.SQL(), cmd.Args()...); err != nil {
+				return fmt.Errorf("glue: %s %s '%s' of table '%s' failed: %v", change.Action, change.Object, change.Name, change.Table, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Empty 没有需要执行的变更，不考虑 Skipped
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// Commands 按执行顺序返回所有命令
+func (p *Plan) Commands() []*internal.Command {
+	commands := make([]*internal.Command, 0)
+	for _, change := range p.Changes {
+		commands = append(commands, change.Commands...)
+	}
+	return commands
+}
+
+// Script 生成 sql 脚本，参数按方言渲染为字面量，每项变更前带有注释，跳过的变更整体注释掉
+func (p *Plan) Script() string {
+	var sb strings.Builder
+	for i, change := range p.Changes {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(fmt.Sprintf("-- %s %s %s\n", change.Action, change.Object, change.describe()))
+		if len(change.Commands) == 0 {
+			sb.WriteString(fmt.Sprintf("-- not supported by %s\n", p.dialect.Name()))
+		}
+		for _, cmd := range change.Commands {
+			sb.WriteString(strings.TrimSpace(internal.Inline(cmd, p.dialect, p.dialect.Literal)))
+			sb.WriteString(";\n")
+		}
+	}
+	for _, change := range p.Skipped {
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(fmt.Sprintf("-- skipped %s %s %s\n", change.Action, change.Object, change.describe()))
+		if len(change.Commands) == 0 {
+			sb.WriteString(fmt.Sprintf("-- not supported by %s\n", p.dialect.Name()))
+		}
+		for _, cmd := range change.Commands {
+			sql := strings.TrimSpace(internal.Inline(cmd, p.dialect, p.dialect.Literal))
+			sb.WriteString("-- " + strings.Replace(sql, "\n", "\n-- ", -1) + ";\n")
+		}
+	}
+	return sb.String()
+}
+
+// WriteTo 将脚本写入 w，可直接保存为 .sql 文件
+func (p *Plan) WriteTo(w io.Writer) (int64, error) {
+	n, err := io.WriteString(w, p.Script())
+	return int64(n), err
+}
+
+func (p *Plan) Summary() *Summary {
+	summary := new(Summary)
+	for _, change := range p.Changes {
+		item := fmt.Sprintf("%s %s", change.Object, change.describe())
+		switch change.Action {
+		case ActionCreate:
+			summary.Created = append(summary.Created, item)
+		case ActionAdd:
+			summary.Added = append(summary.Added, item)
+		case ActionModify:
+			summary.Modified = append(summary.Modified, item)
+		case ActionDrop:
+			summary.Dropped = append(summary.Dropped, item)
+		}
+	}
+	for _, change := range p.Skipped {
+		summary.Skipped = append(summary.Skipped, fmt.Sprintf("%s %s %s", change.Action, change.Object, change.describe()))
+	}
+	return summary
+}
+
+func (s *Summary) String() string {
+	return fmt.Sprintf("%d created, %d added, %d modified, %d dropped, %d skipped", len(s.Created), len(s.Added), len(s.Modified), len(s.Dropped), len(s.Skipped))
+}
+
+func (c *Change) describe() string {
+	if c.Object == ObjectTable {
+		return c.Table
+	}
+	return c.Table + "." + c.Name
+}
@@ -0,0 +1,109 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-02-27 14:20
+// version: 1.0.0
+// desc   : 
+
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/yhyzgn/glue/dialect/sqlite"
+	"github.com/yhyzgn/glue/internal"
+	"strings"
+	"testing"
+)
+
+func TestPlan(t *testing.T) {
+	cases := []struct {
+		name    string
+		setup   string
+		tables  []internal.Table
+		safe    bool
+		summary string
+		items   []string
+		script  []string
+	}{
+		{
+			name:    "create",
+			tables:  []internal.Table{&player{}, &team{}},
+			safe:    true,
+			summary: "2 created, 0 added, 0 modified, 0 dropped, 0 skipped",
+			items:   []string{"table team", "table player"},
+			script:  []string{"-- create table team\nCREATE TABLE \"team\" (", "\n\n-- create table player\nCREATE TABLE \"player\" (", "CREATE INDEX \"idx_player_name\" ON \"player\" (\"name\");\n"},
+		},
+		{
+			name:    "safe",
+			setup:   "CREATE TABLE player (id INTEGER PRIMARY KEY AUTOINCREMENT, name INTEGER, age INTEGER)",
+			tables:  []internal.Table{&playerStrict{}},
+			safe:    true,
+			summary: "0 created, 1 added, 0 modified, 0 dropped, 2 skipped",
+			items:   []string{"index player.idx_player_name", "modify column player.name", "drop column player.age"},
+			script:  []string{"-- add index player.idx_player_name\n", "\n-- skipped drop column player.age\n-- ALTER TABLE \"player\" DROP COLUMN \"age\";\n"},
+		},
+		{
+			name:    "unsafe",
+			setup:   "CREATE TABLE player (id INTEGER PRIMARY KEY AUTOINCREMENT, name INTEGER, age INTEGER)",
+			tables:  []internal.Table{&playerStrict{}},
+			summary: "0 created, 1 added, 1 modified, 1 dropped, 0 skipped",
+			items:   []string{"column player.name", "column player.age", "index player.idx_player_name"},
+			script:  []string{"-- modify column player.name\n", "-- drop column player.age\nALTER TABLE \"player\" DROP COLUMN \"age\";\n"},
+		},
+	}
+
+	dl := sqlite.Dialect()
+	for _, cs := range cases {
+		db, err := sql.Open("sqlite3", ":memory:")
+		if err != nil {
+			t.Fatal(err)
+		}
+		db.SetMaxOpenConns(1)
+		if cs.setup != "" {
+			if _, err := db.Exec(cs.setup); err != nil {
+				t.Fatal(err)
+			}
+		}
+		definitions := make([]*internal.Definition, len(cs.tables))
+		for i, table := range cs.tables {
+			if definitions[i], err = internal.Parse(dl, table); err != nil {
+				t.Fatal(err)
+			}
+		}
+		plan, err := New(dl, db).Safe(cs.safe).Plan(context.Background(), definitions...)
+		_ = db.Close()
+		if err != nil {
+			t.Fatalf("%s: %v", cs.name, err)
+		}
+
+		summary := plan.Summary()
+		items := make([]string, 0)
+		for _, group := range [][]string{summary.Created, summary.Modified, summary.Dropped, summary.Added, summary.Skipped} {
+			items = append(items, group...)
+		}
+		if summary.String() != cs.summary || fmt.Sprint(items) != fmt.Sprint(cs.items) {
+			t.Errorf("%s: unexpected summary %s %v", cs.name, summary, items)
+		}
+		script := plan.Script()
+		for _, part := range cs.script {
+			if !strings.Contains(script, part) {
+				t.Errorf("%s: script should contain %q:\n%s", cs.name, part, script)
+			}
+		}
+	}
+}
@@ -0,0 +1,457 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-01-29 14:10
+// version: 1.0.0
+// desc   : 
+
+package migrate
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"github.com/yhyzgn/glue/dialect"
+	"github.com/yhyzgn/glue/internal"
+	"sort"
+	"time"
+)
+
+const (
+	DefaultHistoryTable = "glue_migration"
+	DefaultLockTable    = "glue_migration_lock"
+)
+
+// LockInterval 迁移锁被占用时的重试间隔
+var LockInterval = time.Second
+
+// LockTimeout 迁移锁的有效期，超时未释放的锁视为持有者已异常退出，会被等待的实例清除，
+// 应大于最长一次迁移的耗时，为 0 时锁不会过期，只能通过 Unlock 释放
+var LockTimeout = 30 * time.Minute
+
+// Func 迁移步骤，executor 在支持事务时为当前步骤的事务
+type Func func(ctx context.Context, executor internal.Executor) error
+
+// Step 一个版本的迁移，Checksum 为空时不校验
+type Step struct {
+	Version  int64
+	Name     string
+	Up       Func
+	Down     Func
+	Checksum string
+}
+
+// Record 已执行的迁移记录，oracle 将空字符串存为 NULL，校验和列可空
+type Record struct {
+	Version   int64     `glue:"pk"`
+	Name      string    `glue:"size:255;notnull"`
+	Checksum  string    `glue:"size:64"`
+	AppliedAt time.Time `glue:"notnull"`
+}
+
+// record 按可空读取校验和的历史记录
+type record struct {
+	Version   int64
+	Name      string
+	Checksum  sql.NullString
+	AppliedAt time.Time
+}
+
+type history struct {
+	internal.TableModel
+	table string
+	Record
+}
+
+type lock struct {
+	internal.TableModel
+	table    string
+	ID       int       `glue:"pk"`
+	Owner    string    `glue:"size:32;notnull"`
+	LockedAt time.Time `glue:"notnull"`
+}
+
+// holder 当前持有锁的实例
+type holder struct {
+	Owner    string
+	LockedAt time.Time
+}
+
+// beginner 能够开启事务的执行器，如 *sql.DB、*sql.Conn
+type beginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// Runner 按版本号顺序执行迁移，已执行的版本记录在历史表中
+//
+// 迁移锁依赖锁表的主键冲突实现互斥，clickhouse 等没有唯一约束的方言上锁不起作用，
+// 需自行保证同一时间只有一个实例执行迁移
+type Runner struct {
+	dialect  internal.Dialect
+	executor internal.Executor
+	history  string
+	lock     string
+	steps    []*Step
+}
+
+func (h *history) TableName() string {
+	return h.table
+}
+
+func (l *lock) TableName() string {
+	return l.table
+}
+
+func NewRunner(dialect internal.Dialect, executor internal.Executor) *Runner {
+	return &Runner{dialect: dialect, executor: executor, history: DefaultHistoryTable, lock: DefaultLockTable}
+}
+
+// Tables 自定义历史表和锁表的表名
+func (r *Runner) Tables(history, lock string) *Runner {
+	r.history, r.lock = history, lock
+	return r
+}
+
+// Register 注册迁移步骤，版本号必须大于已注册的版本
+func (r *Runner) Register(steps ...*Step) error {
+	for _, step := range steps {
+		if step == nil || step.Up == nil {
+			return fmt.Errorf("glue: migration step must have an up func")
+		}
+		if last := len(r.steps); last > 0 && step.Version <= r.steps[last-1].Version {
+			return fmt.Errorf("glue: migration version %d must be greater than %d", step.Version, r.steps[last-1].Version)
+		}
+		r.steps = append(r.steps, step)
+	}
+	return nil
+}
+
+// RegisterCommands 注册由命令组成的迁移步骤，校验和根据 up 命令计算
+func (r *Runner) RegisterCommands(version int64, name string, up, down []*internal.Command) error {
+	step := &Step{Version: version, Name: name, Up: Commands(up...), Checksum: Checksum(up...)}
+	if down != nil {
+		step.Down = Commands(down...)
+	}
+	return r.Register(step)
+}
+
+// Commands 将命令列表包装为迁移步骤
+func Commands(commands ...*internal.Command) Func {
+	return func(ctx context.Context, executor internal.Executor) error {
+		for _, cmd := range commands {
+			if _, err := executor.ExecContext(ctx, cmd.SQL(), cmd.Args()...); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// Checksum 根据命令的 sql 和参数计算校验和
+func Checksum(commands ...*internal.Command) string {
+	hash := sha256.New()
+	for _, cmd := range commands {
+		_, _ = fmt.Fprintf(hash, "%s\n%v\n", cmd.SQL(), cmd.Args())
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Up 执行所有未执行的迁移
+func (r *Runner) Up(ctx context.Context) error {
+	if len(r.steps) == 0 {
+		return nil
+	}
+	return r.To(ctx, r.steps[len(r.steps)-1].Version)
+}
+
+// To 迁移到指定版本，高于该版本的已执行迁移会被回滚，不高于该版本的未执行迁移会被执行
+func (r *Runner) To(ctx context.Context, version int64) error {
+	return r.locked(ctx, func(applied map[int64]*Record) error {
+		for _, v := range sortedVersions(applied) {
+			if v > version && r.step(v) == nil {
+				return fmt.Errorf("glue: can not roll back unregistered migration %d", v)
+			}
+		}
+		for i := len(r.steps) - 1; i >= 0; i-- {
+			if step := r.steps[i]; step.Version > version && applied[step.Version] != nil {
+				if err := r.down(ctx, step); err != nil {
+					return err
+				}
+			}
+		}
+		for _, step := range r.steps {
+			if step.Version <= version && applied[step.Version] == nil {
+				if err := r.up(ctx, step); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// Rollback 回滚最近执行的 n 个迁移
+func (r *Runner) Rollback(ctx context.Context, n int) error {
+	return r.locked(ctx, func(applied map[int64]*Record) error {
+		versions := sortedVersions(applied)
+		for i := len(versions) - 1; i >= 0 && n > 0; i, n = i-1, n-1 {
+			step := r.step(versions[i])
+			if step == nil {
+				return fmt.Errorf("glue: can not roll back unregistered migration %d", versions[i])
+			}
+			if err := r.down(ctx, step); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Applied 按版本号升序返回已执行的迁移
+func (r *Runner) Applied(ctx context.Context) ([]*Record, error) {
+	if err := r.ensure(ctx, &history{table: r.history}); err != nil {
+		return nil, err
+	}
+	cmd := r.dialect.Select(&internal.ExecValue{Table: r.history}).Line("ORDER BY " + r.dialect.Quote("version"))
+	rows, err := r.executor.QueryContext(ctx, cmd.SQL(), cmd.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	scanned := make([]*record, 0)
+	if err := internal.ScanAll(rows, &scanned, internal.UnknownIgnore); err != nil {
+		return nil, err
+	}
+	records := make([]*Record, len(scanned))
+	for i, rc := range scanned {
+		records[i] = &Record{Version: rc.Version, Name: rc.Name, Checksum: rc.Checksum.String, AppliedAt: rc.AppliedAt}
+	}
+	return records, nil
+}
+
+// Validate 校验已执行迁移的校验和与注册的步骤一致
+func (r *Runner) Validate(ctx context.Context) error {
+	records, err := r.Applied(ctx)
+	if err != nil {
+		return err
+	}
+	return r.validate(records)
+}
+
+// Unlock 强制释放迁移锁，不论由哪个实例持有，只应在确认持有者已退出后调用，
+// 超过 LockTimeout 的锁也会被等待的实例自动清除
+func (r *Runner) Unlock(ctx context.Context) error {
+	if err := r.ensure(ctx, &lock{table: r.lock}); err != nil {
+		return err
+	}
+	return r.release(ctx, internal.Eq("id", 1))
+}
+
+// release 删除满足条件的锁记录
+func (r *Runner) release(ctx context.Context, where internal.Condition) error {
+	cmd := r.dialect.Delete(&internal.ExecValue{Table: r.lock, Where: where})
+	_, err := r.executor.ExecContext(ctx, cmd.SQL(), cmd.Args()...)
+	return err
+}
+
+func (r *Runner) validate(records []*Record) error {
+	for _, record := range records {
+		if step := r.step(record.Version); step != nil && step.Checksum != "" && step.Checksum != record.Checksum {
+			return fmt.Errorf("glue: checksum of applied migration %d '%s' does not match", record.Version, record.Name)
+		}
+	}
+	return nil
+}
+
+// locked 持有迁移锁并校验通过后执行 fn
+func (r *Runner) locked(ctx context.Context, fn func(applied map[int64]*Record) error) (err error) {
+	owner, err := r.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// 只释放自己持有的锁，不使用 ctx，避免 ctx 取消后锁无法释放
+		if e := r.release(context.Background(), internal.And(internal.Eq("id", 1), internal.Eq("owner", owner))); err == nil {
+			err = e
+		}
+	}()
+
+	records, err := r.Applied(ctx)
+	if err != nil {
+		return err
+	}
+	if err := r.validate(records); err != nil {
+		return err
+	}
+	applied := make(map[int64]*Record)
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return fn(applied)
+}
+
+// acquire 插入锁记录并返回持有者标识，主键冲突说明锁已被占用，等待后重试，
+// 持有超过 LockTimeout 的锁按原持有者清除后立即重试
+func (r *Runner) acquire(ctx context.Context) (string, error) {
+	if err := r.ensure(ctx, &lock{table: r.lock}); err != nil {
+		return "", err
+	}
+	owner := newOwner()
+	for {
+		cmd := r.dialect.Insert(&internal.ExecValue{Table: r.lock, Columns: []string{"id", "owner", "locked_at"}, Values: []interface{}{1, owner, time.Now()}})
+		_, err := r.executor.ExecContext(ctx, cmd.SQL(), cmd.Args()...)
+		if err == nil {
+			return owner, nil
+		}
+		held, e := r.holder(ctx)
+		if e != nil || held == nil {
+			return "", err
+		}
+		if LockTimeout > 0 && time.Since(held.LockedAt) > LockTimeout {
+			if err := r.release(ctx, internal.And(internal.Eq("id", 1), internal.Eq("owner", held.Owner))); err != nil {
+				return "", err
+			}
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("glue: wait for migration lock held by %s since %s: %v", held.Owner, held.LockedAt.Format(time.RFC3339), ctx.Err())
+		case <-time.After(LockInterval):
+		}
+	}
+}
+
+// holder 查询当前持有锁的实例，锁未被占用时返回 nil
+func (r *Runner) holder(ctx context.Context) (*holder, error) {
+	cmd := r.dialect.Select(&internal.ExecValue{Table: r.lock})
+	rows, err := r.executor.QueryContext(ctx, cmd.SQL(), cmd.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	held := new(holder)
+	if err := internal.ScanOne(rows, held, internal.UnknownIgnore); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return held, nil
+}
+
+// newOwner 生成区分各次加锁的随机标识
+func newOwner() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+func (r *Runner) up(ctx context.Context, step *Step) error {
+	return r.transaction(ctx, func(executor internal.Executor) error {
+		if err := step.Up(ctx, executor); err != nil {
+			return fmt.Errorf("glue: migrate up %d '%s' failed: %v", step.Version, step.Name, err)
+		}
+		cmd := r.dialect.Insert(&internal.ExecValue{
+			Table:   r.history,
+			Columns: []string{"version", "name", "checksum", "applied_at"},
+			Values:  []interface{}{step.Version, step.Name, step.Checksum, time.Now()},
+		})
+		_, err := executor.ExecContext(ctx, cmd.SQL(), cmd.Args()...)
+		return err
+	})
+}
+
+func (r *Runner) down(ctx context.Context, step *Step) error {
+	if step.Down == nil {
+		return fmt.Errorf("glue: migration %d '%s' can not be rolled back", step.Version, step.Name)
+	}
+	return r.transaction(ctx, func(executor internal.Executor) error {
+		if err := step.Down(ctx, executor); err != nil {
+			return fmt.Errorf("glue: migrate down %d '%s' failed: %v", step.Version, step.Name, err)
+		}
+		cmd := r.dialect.Delete(&internal.ExecValue{Table: r.history, Where: internal.Eq("version", step.Version)})
+		_, err := executor.ExecContext(ctx, cmd.SQL(), cmd.Args()...)
+		return err
+	})
+}
+
+// transaction 执行器和方言都支持时在事务中执行迁移和记录，mysql 等数据库的 DDL 会隐式提交
+func (r *Runner) transaction(ctx context.Context, fn func(executor internal.Executor) error) error {
+	db, ok := r.executor.(beginner)
+	if tx, supported := r.dialect.(dialect.Transactional); !ok || supported && !tx.Transactional() {
+		return fn(r.executor)
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// ensure 表不存在时创建，是否存在由方言的 HasTable 在当前 schema 中判断
+func (r *Runner) ensure(ctx context.Context, table internal.Table) error {
+	if exists, err := r.hasTable(ctx, table.TableName()); err != nil || exists {
+		return err
+	}
+	def, err := internal.Parse(r.dialect, table)
+	if err != nil {
+		return err
+	}
+	for _, cmd := range r.dialect.CreateTable(def) {
+		if _, err := r.executor.ExecContext(ctx, cmd.SQL(), cmd.Args()...); err != nil {
+			// 其他实例可能同时创建了该表
+			if exists, _ := r.hasTable(ctx, table.TableName()); exists {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Runner) hasTable(ctx context.Context, name string) (bool, error) {
+	return dialect.Exists(ctx, r.dialect, r.executor, func(schema string) *internal.Command {
+		return r.dialect.HasTable(schema, name)
+	})
+}
+
+func (r *Runner) step(version int64) *Step {
+	idx := sort.Search(len(r.steps), func(i int) bool {
+		return r.steps[i].Version >= version
+	})
+	if idx < len(r.steps) && r.steps[idx].Version == version {
+		return r.steps[idx]
+	}
+	return nil
+}
+
+func sortedVersions(applied map[int64]*Record) []int64 {
+	versions := make([]int64, 0, len(applied))
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i] < versions[j]
+	})
+	return versions
+}
@@ -0,0 +1,146 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-01-29 15:30
+// version: 1.0.0
+// desc   : 
+
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"github.com/yhyzgn/glue/dialect/sqlite"
+	"github.com/yhyzgn/glue/internal"
+	"testing"
+	"time"
+)
+
+func TestRunner(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	ctx := context.Background()
+	newRunner := func() *Runner {
+		runner := NewRunner(sqlite.Dialect(), db)
+		steps := []struct {
+			version  int64
+			up, down string
+		}{
+			{1, "CREATE TABLE a (id INTEGER)", "DROP TABLE a"},
+			{2, "CREATE TABLE b (id INTEGER)", "DROP TABLE b"},
+			{3, "CREATE TABLE c (id INTEGER)", "DROP TABLE c"},
+		}
+		for _, step := range steps {
+			if err := runner.RegisterCommands(step.version, step.up, []*internal.Command{internal.NewCommand(step.up)}, []*internal.Command{internal.NewCommand(step.down)}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return runner
+	}
+	versions := func(runner *Runner) []int64 {
+		records, err := runner.Applied(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := make([]int64, len(records))
+		for i, record := range records {
+			result[i] = record.Version
+		}
+		return result
+	}
+
+	runner := newRunner()
+	if err := runner.Register(&Step{Version: 2, Up: Commands()}); err == nil {
+		t.Error("expect error of non increasing version")
+	}
+	if err := runner.To(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+	if applied := versions(runner); len(applied) != 2 || applied[1] != 2 {
+		t.Errorf("unexpected applied versions %v", applied)
+	}
+	if err := runner.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := runner.Rollback(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+	if applied := versions(runner); len(applied) != 1 || applied[0] != 1 {
+		t.Errorf("unexpected applied versions %v", applied)
+	}
+	if _, err := db.Exec("SELECT * FROM b"); err == nil {
+		t.Error("table b should have been dropped")
+	}
+
+	changed := NewRunner(sqlite.Dialect(), db)
+	if err := changed.RegisterCommands(1, "changed", []*internal.Command{internal.NewCommand("CREATE TABLE a (id BIGINT)")}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := changed.Validate(ctx); err == nil {
+		t.Error("expect error of checksum mismatch")
+	}
+
+	if _, err := runner.acquire(ctx); err != nil {
+		t.Fatal(err)
+	}
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := runner.Up(canceled); err == nil {
+		t.Error("expect error while the lock is held")
+	}
+	if err := runner.Unlock(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := runner.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// 只释放自己持有的锁
+	owner, err := runner.acquire(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := runner.release(ctx, internal.And(internal.Eq("id", 1), internal.Eq("owner", "other"))); err != nil {
+		t.Fatal(err)
+	}
+	if held, err := runner.holder(ctx); err != nil || held == nil || held.Owner != owner {
+		t.Fatalf("lock of %s should be kept: %v %v", owner, held, err)
+	}
+	// 超时的锁被清除，Go 函数迁移的空校验和按 oracle 的方式存为 NULL 也能读取
+	if _, err := db.Exec("UPDATE glue_migration_lock SET locked_at = ?", time.Now().Add(-2*LockTimeout)); err != nil {
+		t.Fatal(err)
+	}
+	if err := runner.Register(&Step{Version: 4, Name: "func", Up: Commands(internal.NewCommand("CREATE TABLE d (id INTEGER)"))}); err != nil {
+		t.Fatal(err)
+	}
+	if err := runner.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("UPDATE glue_migration SET checksum = NULL WHERE version = 4"); err != nil {
+		t.Fatal(err)
+	}
+	if applied := versions(runner); len(applied) != 4 || applied[3] != 4 {
+		t.Errorf("unexpected applied versions %v", applied)
+	}
+	if held, err := runner.holder(ctx); err != nil || held != nil {
+		t.Errorf("lock should be released: %v %v", held, err)
+	}
+}
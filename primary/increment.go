@@ -20,8 +20,15 @@
 
 package primary
 
+import "github.com/yhyzgn/glue/internal"
+
 type AutoIncrement struct{}
 
 func (*AutoIncrement) Primary() interface{} {
 	return nil
 }
+
+// Declare 自增主键在所有方言中都使用标识列语法
+func (*AutoIncrement) Declare(dialect internal.Dialect) *internal.Declaration {
+	return &internal.Declaration{Identity: true}
+}
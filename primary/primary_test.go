@@ -0,0 +1,107 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-02-26 09:30
+// version: 1.0.0
+// desc   : 
+
+package primary
+
+import (
+	"regexp"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestUUID(t *testing.T) {
+	pattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-([47])[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	for _, version := range []int{0, V4, V7} {
+		id, ok := (&UUID{Version: version}).Primary().(string)
+		match := pattern.FindStringSubmatch(id)
+		expected := "4"
+		if version == V7 {
+			expected = "7"
+		}
+		if !ok || match == nil || match[1] != expected {
+			t.Errorf("unexpected uuid v%d: %v", version, id)
+		}
+	}
+	if id, ok := (&UUID{Version: V7, Binary: true}).Primary().([]byte); !ok || len(id) != 16 || id[6]>>4 != 7 {
+		t.Errorf("unexpected binary uuid: %v", id)
+	}
+
+	// v7 按时间排序
+	now := time.Now()
+	earlier, later := newUUIDv7(now), newUUIDv7(now.Add(time.Millisecond))
+	if FormatUUID(earlier) >= FormatUUID(later) {
+		t.Errorf("uuid v7 should be ordered by time: %s %s", FormatUUID(earlier), FormatUUID(later))
+	}
+}
+
+func TestULID(t *testing.T) {
+	id := (&ULID{}).Primary().(string)
+	if !regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`).MatchString(id) {
+		t.Errorf("unexpected ulid: %s", id)
+	}
+	now := time.Now()
+	if earlier, later := newULID(now), newULID(now.Add(time.Millisecond)); earlier >= later {
+		t.Errorf("ulid should be ordered by time: %s %s", earlier, later)
+	}
+	// 时间戳部分固定为 10 个字符
+	if prefix := newULID(time.Unix(0, 0))[:10]; prefix != "0000000000" {
+		t.Errorf("unexpected ulid time prefix: %s", prefix)
+	}
+}
+
+func TestSnowflake(t *testing.T) {
+	if _, err := NewSnowflake(SnowflakeConfig{DatacenterBits: 5, WorkerBits: 5, SequenceBits: 13}); err == nil {
+		t.Error("too many bits should be rejected")
+	}
+	if _, err := NewSnowflake(SnowflakeConfig{Worker: 32}); err == nil {
+		t.Error("worker out of range should be rejected")
+	}
+
+	sf, err := NewSnowflake(SnowflakeConfig{DatacenterBits: 1, WorkerBits: 1, SequenceBits: 2, Datacenter: 1, Worker: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := sf.epoch + 100
+	sf.now = func() int64 {
+		return clock
+	}
+	ids := make([]int64, 0)
+	for i := 0; i < 6; i++ {
+		ids = append(ids, sf.Next())
+	}
+	// 时钟回拨
+	clock -= 50
+	for i := 0; i < 3; i++ {
+		ids = append(ids, sf.Primary().(int64))
+	}
+	if !sort.SliceIsSorted(ids, func(i, j int) bool { return ids[i] < ids[j] }) {
+		t.Fatalf("snowflake ids should increase: %v", ids)
+	}
+	for i := 1; i < len(ids); i++ {
+		if ids[i] == ids[i-1] {
+			t.Fatalf("duplicated snowflake id %d", ids[i])
+		}
+	}
+	// 序号只有 2 位，第 5 个起借用下一毫秒
+	if ids[0]>>4 != 100 || ids[4]>>4 != 101 || ids[0]>>2&3 != 3 {
+		t.Errorf("unexpected snowflake layout: %b %b", ids[0], ids[4])
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-02-25 16:30
+// version: 1.0.0
+// desc   : 
+
+package primary
+
+import (
+	"fmt"
+	"github.com/yhyzgn/glue/internal"
+)
+
+// Sequence 由数据库序列生成主键，插入时写入取序列下一个值的表达式（postgres 的 nextval、oracle 的 NEXTVAL、
+// mssql 的 NEXT VALUE FOR），生成的值通过 RETURNING 等子句取回。序列随建表语句一起创建，见 dialect.Sequencer
+type Sequence struct {
+	Name string
+}
+
+func (s *Sequence) Primary() interface{} {
+	return nextValue(s.Name)
+}
+
+// Declare 建表前先创建序列，不支持序列的方言不生成额外语句，插入时再报错
+func (s *Sequence) Declare(dialect internal.Dialect) *internal.Declaration {
+	sequencer, ok := dialect.(interface {
+		CreateSequence(name string, start, increment int64) *internal.Command
+	})
+	if !ok {
+		return nil
+	}
+	return &internal.Declaration{Commands: []*internal.Command{sequencer.CreateSequence(s.Name, 1, 1)}}
+}
+
+type nextValue string
+
+func (n nextValue) Expression(dialect internal.Dialect) (string, error) {
+	// 与 dialect.Sequencer 一致，dialect 包依赖本包，这里不能直接引用
+	sequencer, ok := dialect.(interface {
+		NextValue(name string) string
+	})
+	if !ok {
+		return "", fmt.Errorf("glue: dialect %s does not support sequences", dialect.Name())
+	}
+	return sequencer.NextValue(string(n)), nil
+}
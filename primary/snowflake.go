@@ -0,0 +1,104 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-02-25 14:10
+// version: 1.0.0
+// desc   : 
+
+package primary
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultEpoch Snowflake 默认的起始时间
+var DefaultEpoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// SnowflakeConfig 各部分位数都为 0 时使用 5 位数据中心、5 位机器和 12 位序号，其余 41 位为毫秒时间戳
+type SnowflakeConfig struct {
+	Epoch          time.Time
+	DatacenterBits uint
+	WorkerBits     uint
+	SequenceBits   uint
+	Datacenter     int64
+	Worker         int64
+}
+
+// Snowflake Twitter 风格的 64 位递增主键，同一实例并发安全，需要在各表的 PrimaryStrategy 中共用。
+// 时钟回拨时沿用上次的时间戳继续分配序号，序号用尽后借用下一毫秒，保证不重复且递增
+type Snowflake struct {
+	mu          sync.Mutex
+	epoch       int64
+	node        int64
+	timeShift   uint
+	maxSequence int64
+	last        int64
+	sequence    int64
+	now         func() int64
+}
+
+func NewSnowflake(config SnowflakeConfig) (*Snowflake, error) {
+	if config.DatacenterBits == 0 && config.WorkerBits == 0 && config.SequenceBits == 0 {
+		config.DatacenterBits, config.WorkerBits, config.SequenceBits = 5, 5, 12
+	}
+	if config.SequenceBits == 0 || config.DatacenterBits+config.WorkerBits+config.SequenceBits > 22 {
+		return nil, fmt.Errorf("glue: invalid snowflake bits %d/%d/%d, sequence bits must be positive and the sum at most 22",
+			config.DatacenterBits, config.WorkerBits, config.SequenceBits)
+	}
+	if config.Datacenter < 0 || config.Datacenter >= 1<<config.DatacenterBits {
+		return nil, fmt.Errorf("glue: snowflake datacenter %d out of range [0, %d)", config.Datacenter, int64(1)<<config.DatacenterBits)
+	}
+	if config.Worker < 0 || config.Worker >= 1<<config.WorkerBits {
+		return nil, fmt.Errorf("glue: snowflake worker %d out of range [0, %d)", config.Worker, int64(1)<<config.WorkerBits)
+	}
+	if config.Epoch.IsZero() {
+		config.Epoch = DefaultEpoch
+	}
+	return &Snowflake{
+		epoch:       config.Epoch.UnixNano() / int64(time.Millisecond),
+		node:        (config.Datacenter<<config.WorkerBits | config.Worker) << config.SequenceBits,
+		timeShift:   config.DatacenterBits + config.WorkerBits + config.SequenceBits,
+		maxSequence: 1<<config.SequenceBits - 1,
+		now: func() int64 {
+			return time.Now().UnixNano() / int64(time.Millisecond)
+		},
+	}, nil
+}
+
+func (s *Snowflake) Primary() interface{} {
+	return s.Next()
+}
+
+func (s *Snowflake) Next() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now() - s.epoch
+	if now < s.last {
+		now = s.last
+	}
+	if now == s.last {
+		s.sequence = (s.sequence + 1) & s.maxSequence
+		if s.sequence == 0 {
+			now++
+		}
+	} else {
+		s.sequence = 0
+	}
+	s.last = now
+	return now<<s.timeShift | s.node | s.sequence
+}
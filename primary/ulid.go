@@ -0,0 +1,55 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-02-25 11:20
+// version: 1.0.0
+// desc   : 
+
+package primary
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+// crockford base32，去掉了易混淆的 I、L、O、U
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ULID 生成 26 位的 ULID 字符串主键，前 48 位为毫秒时间戳，按生成时间排序
+type ULID struct{}
+
+func (*ULID) Primary() interface{} {
+	return newULID(time.Now())
+}
+
+func newULID(now time.Time) string {
+	var id [16]byte
+	var ms [8]byte
+	binary.BigEndian.PutUint64(ms[:], uint64(now.UnixNano()/int64(time.Millisecond)))
+	copy(id[:6], ms[2:])
+	random(id[6:])
+
+	// 128 位按 5 位一组编码，首字符只有 3 位有效
+	hi := binary.BigEndian.Uint64(id[:8])
+	lo := binary.BigEndian.Uint64(id[8:])
+	buf := make([]byte, 26)
+	for i := 25; i >= 0; i-- {
+		buf[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(buf)
+}
@@ -0,0 +1,94 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-02-25 10:00
+// version: 1.0.0
+// desc   : 
+
+package primary
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"time"
+)
+
+// UUID 版本
+const (
+	V4 = 4
+	V7 = 7
+)
+
+// UUID 生成 UUID 主键，Version 为 V4（随机）或 V7（毫秒时间戳开头，按生成时间递增，索引更友好），默认 V4。
+// Binary 为 true 时返回 16 字节的 []byte，适用于 BINARY(16) 列，否则返回 36 位的标准字符串
+type UUID struct {
+	Version int
+	Binary  bool
+}
+
+func (u *UUID) Primary() interface{} {
+	var id [16]byte
+	if u.Version == V7 {
+		id = newUUIDv7(time.Now())
+	} else {
+		id = newUUIDv4()
+	}
+	if u.Binary {
+		return id[:]
+	}
+	return FormatUUID(id)
+}
+
+// FormatUUID 按 8-4-4-4-12 格式输出
+func FormatUUID(id [16]byte) string {
+	buf := make([]byte, 36)
+	hex.Encode(buf[0:8], id[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], id[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], id[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], id[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], id[10:])
+	return string(buf)
+}
+
+func newUUIDv4() (id [16]byte) {
+	random(id[:])
+	id[6] = id[6]&0x0f | 0x40
+	id[8] = id[8]&0x3f | 0x80
+	return
+}
+
+// newUUIDv7 前 48 位为 unix 毫秒时间戳，其余为随机数
+func newUUIDv7(now time.Time) (id [16]byte) {
+	random(id[6:])
+	var ms [8]byte
+	binary.BigEndian.PutUint64(ms[:], uint64(now.UnixNano()/int64(time.Millisecond)))
+	copy(id[:6], ms[2:])
+	id[6] = id[6]&0x0f | 0x70
+	id[8] = id[8]&0x3f | 0x80
+	return
+}
+
+// random 系统随机数不可用时无法保证主键唯一，直接 panic
+func random(b []byte) {
+	if _, err := rand.Read(b); err != nil {
+		panic("glue: crypto/rand unavailable: " + err.Error())
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-02-20 14:30
+// version: 1.0.0
+// desc   : 
+
+package glue
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy 重试策略，MaxAttempts 包含首次执行，等待时间从 Backoff 开始每次翻倍，不超过 MaxBackoff，
+// MaxBackoff 为 0 时使用 DefaultRetryPolicy 的 MaxBackoff
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+}
+
+// DefaultRetryPolicy 默认最多执行 3 次
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 3, Backoff: 10 * time.Millisecond, MaxBackoff: time.Second}
+
+// Retry 执行 fn，返回的错误经 dialect.Classify 判断为可重试时等待后重新执行，
+// 直到成功、遇到不可重试的错误、次数用尽或 ctx 结束，返回最后一次的错误。policy 为 nil 时使用 DefaultRetryPolicy
+func Retry(ctx context.Context, dialect Dialect, policy *RetryPolicy, fn func() error) error {
+	if policy == nil {
+		policy = &DefaultRetryPolicy
+	}
+	if policy.Backoff < 0 || policy.MaxBackoff < 0 {
+		return fmt.Errorf("glue: negative retry backoff %v, max backoff %v", policy.Backoff, policy.MaxBackoff)
+	}
+	limit := policy.MaxBackoff
+	if limit == 0 {
+		limit = DefaultRetryPolicy.MaxBackoff
+	}
+	backoff := policy.Backoff
+	if backoff > limit {
+		backoff = limit
+	}
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= policy.MaxAttempts || !dialect.Classify(err).Retryable() {
+			return err
+		}
+		// 随机化等待时间，避免冲突的事务同时重试再次冲突
+		wait := backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+		backoff = nextBackoff(backoff, limit)
+	}
+}
+
+// nextBackoff 翻倍等待时间，不超过 limit，也不会溢出
+func nextBackoff(backoff, limit time.Duration) time.Duration {
+	if backoff > math.MaxInt64/2 || backoff*2 > limit {
+		return limit
+	}
+	return backoff * 2
+}
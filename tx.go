@@ -0,0 +1,134 @@
+// Copyright 2020 yhyzgn glue
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2020-02-18 10:30
+// version: 1.0.0
+// desc   : 
+
+package glue
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/yhyzgn/glue/dialect"
+)
+
+type txKey struct{}
+
+// TxOptions 事务选项，嵌套调用时忽略
+type TxOptions struct {
+	Isolation sql.IsolationLevel
+	ReadOnly  bool
+	// Dialect 用于生成保存点语句和分类错误，为空时按 db 的驱动查找
+	Dialect Dialect
+	// Retry 不为空时，事务因可重试的错误失败后按策略重新执行整个 fn，fn 需能安全地重复执行
+	Retry *RetryPolicy
+}
+
+// Tx WithTx 开启的事务，实现了 Executor
+type Tx struct {
+	*sql.Tx
+	db         *sql.DB
+	dialect    Dialect
+	ctx        context.Context
+	savepoints int
+}
+
+// Context 返回绑定了当前事务的 context，用它调用 WithTx 时在当前事务中嵌套保存点
+func (tx *Tx) Context() context.Context {
+	return tx.ctx
+}
+
+func (tx *Tx) Dialect() Dialect {
+	return tx.dialect
+}
+
+// WithTx 在事务中执行 fn，fn 返回 error 或 panic 时回滚，否则提交，panic 会转为 error 返回，数据库错误经 Translate 转换。
+// ctx 中已有同一 db 的事务时（见 Tx.Context）不再开启新事务，而是在保存点中执行 fn，失败时只回滚到保存点，也不会重试
+func WithTx(ctx context.Context, db *sql.DB, opts *TxOptions, fn func(tx *Tx) error) error {
+	if tx, ok := ctx.Value(txKey{}).(*Tx); ok && tx.db == db {
+		return tx.nested(ctx, fn)
+	}
+	if opts == nil {
+		opts = &TxOptions{}
+	}
+	dl := opts.Dialect
+	if dl == nil {
+		found, err := dialect.FromDB(db)
+		if err != nil {
+			return err
+		}
+		dl = found
+	}
+	if transactional, ok := dl.(dialect.Transactional); ok && !transactional.Transactional() {
+		return fmt.Errorf("glue: %s does not support transactions", dl.Name())
+	}
+
+	if opts.Retry == nil {
+		return dl.Translate(withTx(ctx, db, dl, opts, fn))
+	}
+	return dl.Translate(Retry(ctx, dl, opts.Retry, func() error {
+		return withTx(ctx, db, dl, opts, fn)
+	}))
+}
+
+func withTx(ctx context.Context, db *sql.DB, dl Dialect, opts *TxOptions, fn func(tx *Tx) error) error {
+	sqlTx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: opts.Isolation, ReadOnly: opts.ReadOnly})
+	if err != nil {
+		return err
+	}
+	tx := &Tx{Tx: sqlTx, db: db, dialect: dl}
+	tx.ctx = context.WithValue(ctx, txKey{}, tx)
+	if err := tx.run(fn); err != nil {
+		_ = sqlTx.Rollback()
+		return err
+	}
+	return sqlTx.Commit()
+}
+
+// nested 在保存点中执行 fn，保存点名称在事务内递增，避免同名保存点互相覆盖，保存点语句使用调用方的 ctx
+func (tx *Tx) nested(ctx context.Context, fn func(tx *Tx) error) error {
+	tx.savepoints++
+	name := fmt.Sprintf("glue_sp_%d", tx.savepoints)
+	if err := tx.exec(ctx, tx.dialect.Savepoint(name)); err != nil {
+		return err
+	}
+	if err := tx.run(fn); err != nil {
+		if e := tx.exec(ctx, tx.dialect.RollbackTo(name)); e != nil {
+			return fmt.Errorf("glue: %v, and rollback to savepoint failed: %v", err, e)
+		}
+		return err
+	}
+	return tx.exec(ctx, tx.dialect.ReleaseSavepoint(name))
+}
+
+func (tx *Tx) run(fn func(tx *Tx) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("glue: transaction panic: %v", r)
+		}
+	}()
+	return fn(tx)
+}
+
+func (tx *Tx) exec(ctx context.Context, cmd *Command) error {
+	if cmd == nil {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, cmd.SQL(), cmd.Args()...)
+	return err
+}